@@ -11,6 +11,22 @@
 //	diagram := erd.FromSchema("My Domain", sentinel.Schema())
 //	fmt.Println(diagram.ToMermaid())
 //
+// # Static Analysis (via go/packages)
+//
+// Use [FromPackages] to scan Go source without compiling a program:
+//
+//	diagram, err := erd.FromPackages("./internal/models/...")
+//
+// # Import from SQL
+//
+// Use [FromSQL] to build a diagram from existing DDL such as migration files:
+//
+//	diagram, err := erd.FromSQL("Billing", file, erd.PostgreSQL)
+//
+// Use [FromDatabase] to introspect a live database instead:
+//
+//	diagram, err := erd.FromDatabase(ctx, db, erd.DatabaseOptions{Dialect: erd.PostgreSQL})
+//
 // # Manual Construction (via Builder)
 //
 // Use [NewDiagram], [NewEntity], [NewAttribute], and [NewRelationship] to
@@ -26,6 +42,53 @@
 // Diagrams can be rendered to multiple formats:
 //   - [Diagram.ToMermaid] - Mermaid syntax for web rendering
 //   - [Diagram.ToDOT] - GraphViz DOT format for high-quality output
+//   - [Diagram.ToPlantUML] - PlantUML entity-relationship syntax
+//   - [Diagram.ToDBML] - DBML for dbdiagram.io and dbdocs
+//   - [Diagram.ToSQL] - CREATE TABLE statements for PostgreSQL, MySQL or SQLite
+//
+// The Mermaid, DOT, PlantUML and DBML renderers have With variants, such as
+// [Diagram.ToDOTWith], that accept [RenderOptions] to hide attributes, types,
+// notes or the title, show full types, set the DOT layout direction, draw DOT
+// entities as HTML-like tables that connect relationships to the attributes
+// they join, or group entities by package.
+//
+// [Diagram.WriteMermaid] and [Diagram.WriteDOT] stream to an [io.Writer]
+// one entity at a time, for diagrams too large to build as a string.
+//
+// [Render] and [RenderWith] look renderers up by [Format] name, so callers
+// such as HTTP handlers can take the format as input. [SQLFormat] writes the
+// DDL of [Diagram.ToSQL] in [RenderOptions.Dialect]. Use [RegisterRenderer]
+// to add formats of your own or replace the built-in ones.
+//
+// # JSON
+//
+// Use [Diagram.WriteJSON] and [ReadJSON] to store diagrams as versioned JSON
+// documents and load them back for rendering or validation.
+// [JSONSchema] describes the format, and [ValidateJSON] checks hand-authored
+// documents against it.
+//
+// # Diff
+//
+// Use [Diff] to list the structural changes between two versions of a diagram.
+// [RenderDiff] draws both versions as one Mermaid or DOT diagram with
+// additions, removals and modifications highlighted.
+// [CheckCompatibility] classifies changes as breaking or safe for consumers
+// of the old diagram.
+// [MigrationSQL] generates forward and rollback SQL between the two.
+//
+// # Merge
+//
+// Use [Merge] to combine diagrams, such as one per service, into a single
+// view. Conflicting entity and relationship definitions are reported as
+// [Conflict] values instead of being overwritten.
+//
+// # Subgraphs
+//
+// Use [Diagram.Neighborhood] to extract the entities within a number of
+// relationship hops of one entity as a new diagram.
+//
+// Use [Diagram.Filter] to keep or drop entities by name or package and
+// attributes by name, such as to hide internal structs and audit columns.
 //
 // # Struct Tags
 //
@@ -33,9 +96,45 @@
 //   - pk: Primary key
 //   - fk: Foreign key
 //   - uk: Unique key
+//   - fk:Entity, fk:Entity.Attribute: Foreign key referencing another entity
+//   - fk:Entity.Attribute:name: Member of the named composite foreign key
+//   - uk:name: Member of the named unique key
 //   - note:...: Attribute annotation
 //
-// Tags can be combined: `erd:"pk,note:Auto-generated UUID"`
+// Tags can be combined: `erd:"pk,note:Auto-generated UUID"`. Keys beyond an
+// attribute's first, foreign keys with a reference and named unique keys are
+// recorded in [Entity.Keys], where attributes sharing an fk: name for the
+// same entity form a composite foreign key.
+//
+// # Foreign Keys
+//
+// [Relationship.FromAttributes] and [Relationship.ToAttributes] record the
+// attributes a relationship joins. [FromSchema] records them for attributes
+// tagged fk:Entity, adding a relationship where no field relates the two
+// entities. [FromSchemaWith] can also infer them from attribute names such
+// as UserID and user_id; see [SchemaOptions].
+//
+// # Participation
+//
+// [Relationship.FromParticipation] and [Relationship.ToParticipation] set
+// the minimum and maximum number of entities at each end of a relationship.
+// [FromSchema] sets the target end from the field: zero or one for pointers,
+// exactly one for struct values and zero or more for collections.
+//
+// # Keys
+//
+// [Attribute.Key] gives an attribute a single key. Keys spanning several
+// attributes, or giving an attribute more than one key, are declared with
+// [Entity.AddKey]. Renderers show the union of both.
+//
+// # Entity Identity
+//
+// Entities are keyed by name in [Diagram.Entities]. Entities from different
+// packages that share a name are keyed by [Entity.QualifiedName] instead,
+// and relationships refer to them by that key. An entity added again under
+// the same qualified name is kept under a numbered key, such as User_2, and
+// reported by [Diagram.Validate]. Use [Diagram.Entity] to look entities up by
+// key, qualified name or unambiguous bare name.
 //
 // # Validation
 //
@@ -51,11 +150,16 @@ type Diagram struct {
 }
 
 // Entity represents a domain model entity (typically a Go struct).
+//
+// Attributes carrying a single key are marked by [Attribute.Key]. Keys
+// spanning several attributes, attributes with more than one key, and
+// foreign keys naming what they reference are declared in Keys.
 type Entity struct {
 	Package    *string
 	Note       *string
 	Name       string
 	Attributes []*Attribute
+	Keys       []*Key
 }
 
 // Attribute represents a field/property of an entity.
@@ -77,14 +181,44 @@ const (
 	UniqueKey  KeyType = "UK"
 )
 
+// Key is a named group of attributes forming a primary, unique or foreign
+// key of an entity. A key listing several attributes is composite.
+type Key struct {
+	Reference  *KeyReference
+	Name       string
+	Type       KeyType
+	Attributes []string
+}
+
+// KeyReference is the entity a foreign key refers to, and the attributes of
+// that entity matching the key's attributes in order. Without attributes the
+// key refers to the entity's primary key.
+type KeyReference struct {
+	Entity     string
+	Attributes []string
+}
+
 // Relationship represents a relationship between entities.
+//
+// FromParticipation and ToParticipation refine the cardinality with how many
+// entities take part at each end: ToParticipation is how many To entities
+// one From entity relates to. An end left unset is exactly one on a one end
+// of the cardinality and zero or more on a many end.
+//
+// FromAttributes and ToAttributes record the attributes the relationship
+// joins, when known: a foreign key on one entity and the key of the other it
+// refers to, in matching order.
 type Relationship struct {
-	Label       *string
-	Note        *string
-	From        string
-	To          string
-	Field       string
-	Cardinality Cardinality
+	Label             *string
+	Note              *string
+	FromParticipation *Participation
+	ToParticipation   *Participation
+	From              string
+	To                string
+	Field             string
+	Cardinality       Cardinality
+	FromAttributes    []string
+	ToAttributes      []string
 }
 
 // Cardinality represents the type of relationship between entities.
@@ -97,3 +231,15 @@ const (
 	ManyToOne  Cardinality = "many-to-one"
 	ManyToMany Cardinality = "many-to-many"
 )
+
+// Participation is the minimum and maximum number of entities at one end of
+// a relationship.
+type Participation string
+
+// Participation constants.
+const (
+	ZeroOrOne  Participation = "0..1"
+	ExactlyOne Participation = "1..1"
+	ZeroOrMore Participation = "0..N"
+	OneOrMore  Participation = "1..N"
+)
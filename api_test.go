@@ -57,6 +57,88 @@ func TestDiagram_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "Relationship[0].To: entity 'NonExistent' does not exist",
 		},
+		{
+			name: "relationship participation",
+			diagram: NewDiagram("Test").
+				AddEntity(NewEntity("User").AddAttribute(NewAttribute("ID", "string"))).
+				AddEntity(NewEntity("Order").AddAttribute(NewAttribute("ID", "string"))).
+				AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany).
+					WithFromParticipation(ExactlyOne).
+					WithToParticipation(OneOrMore)),
+			wantErr: false,
+		},
+		{
+			name: "relationship attributes",
+			diagram: NewDiagram("Test").
+				AddEntity(NewEntity("User").AddAttribute(NewAttribute("ID", "string"))).
+				AddEntity(NewEntity("Order").AddAttribute(NewAttribute("UserID", "string"))).
+				AddRelationship(NewRelationship("Order", "User", "UserID", ManyToOne).
+					WithAttributes([]string{"UserID"}, []string{"Email"})),
+			wantErr: true,
+			errMsg:  "Relationship[0].ToAttributes: attribute 'Email' does not exist on entity 'User'",
+		},
+		{
+			name: "relationship attribute count mismatch",
+			diagram: NewDiagram("Test").
+				AddEntity(NewEntity("User").AddAttribute(NewAttribute("ID", "string"))).
+				AddEntity(NewEntity("Order").AddAttribute(NewAttribute("UserID", "string"))).
+				AddRelationship(NewRelationship("Order", "User", "UserID", ManyToOne).
+					WithAttributes([]string{"UserID"}, nil)),
+			wantErr: true,
+			errMsg:  "Relationship[0].ToAttributes: relationship joins 1 attribute(s) of 'Order' but 0 of 'User'",
+		},
+		{
+			name: "invalid participation",
+			diagram: NewDiagram("Test").
+				AddEntity(NewEntity("User").AddAttribute(NewAttribute("ID", "string"))).
+				AddEntity(NewEntity("Profile").AddAttribute(NewAttribute("ID", "string"))).
+				AddRelationship(NewRelationship("User", "Profile", "Profile", OneToOne).
+					WithToParticipation("0..2")),
+			wantErr: true,
+			errMsg:  "Relationship[0].ToParticipation: invalid participation: 0..2",
+		},
+		{
+			name: "participation contradicts cardinality",
+			diagram: NewDiagram("Test").
+				AddEntity(NewEntity("User").AddAttribute(NewAttribute("ID", "string"))).
+				AddEntity(NewEntity("Order").AddAttribute(NewAttribute("ID", "string"))).
+				AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany).
+					WithFromParticipation(ZeroOrMore)),
+			wantErr: true,
+			errMsg:  "Relationship[0].FromParticipation: participation 0..N does not match cardinality one-to-many",
+		},
+		{
+			name:    "composite keys",
+			diagram: testCompositeDiagram(),
+			wantErr: false,
+		},
+		{
+			name: "key references missing entity",
+			diagram: NewDiagram("Test").
+				AddEntity(NewEntity("Order").
+					AddAttribute(NewAttribute("UserID", "string")).
+					AddKey(NewKey("fk_user", ForeignKey, "UserID").WithReference("User", "ID"))),
+			wantErr: true,
+			errMsg:  "Entity[Order].Key[0].Reference.Entity: entity 'User' does not exist",
+		},
+		{
+			name: "key references missing attribute",
+			diagram: testCompositeDiagram().
+				AddEntity(NewEntity("Shipment").
+					AddAttribute(NewAttribute("OrderID", "string")).
+					AddKey(NewKey("fk_order", ForeignKey, "OrderID").WithReference("Order", "Code"))),
+			wantErr: true,
+			errMsg:  "Entity[Shipment].Key[0].Reference.Attributes: attribute 'Code' does not exist on entity 'Order'",
+		},
+		{
+			name: "key reference count mismatch",
+			diagram: testCompositeDiagram().
+				AddEntity(NewEntity("Shipment").
+					AddAttribute(NewAttribute("OrderID", "string")).
+					AddKey(NewKey("fk_order", ForeignKey, "OrderID").WithReference("Order", "TenantID", "ID"))),
+			wantErr: true,
+			errMsg:  "Entity[Shipment].Key[0].Reference.Attributes: key has 1 attribute(s) but references 2",
+		},
 	}
 
 	for _, tt := range tests {
@@ -116,6 +198,38 @@ func TestEntity_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "Attribute[0].Name: attribute name is required",
 		},
+		{
+			name: "key with missing attribute",
+			entity: NewEntity("User").
+				AddAttribute(NewAttribute("ID", "string")).
+				AddKey(NewKey("uk_email", UniqueKey, "ID", "Email")),
+			wantErr: true,
+			errMsg:  "Key[0].Attributes: attribute 'Email' does not exist",
+		},
+		{
+			name: "key without attributes",
+			entity: NewEntity("User").
+				AddAttribute(NewAttribute("ID", "string")).
+				AddKey(NewKey("pk", PrimaryKey)),
+			wantErr: true,
+			errMsg:  "Key[0].Attributes: key must list at least one attribute",
+		},
+		{
+			name: "invalid key type",
+			entity: NewEntity("User").
+				AddAttribute(NewAttribute("ID", "string")).
+				AddKey(NewKey("ix", "IX", "ID")),
+			wantErr: true,
+			errMsg:  "Key[0].Type: invalid key type: IX",
+		},
+		{
+			name: "reference on unique key",
+			entity: NewEntity("User").
+				AddAttribute(NewAttribute("ID", "string")).
+				AddKey(NewKey("uk", UniqueKey, "ID").WithReference("Account")),
+			wantErr: true,
+			errMsg:  "Key[0].Reference: only foreign keys can reference another entity",
+		},
 	}
 
 	for _, tt := range tests {
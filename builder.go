@@ -17,8 +17,19 @@ func (d *Diagram) WithDescription(description string) *Diagram {
 }
 
 // AddEntity adds an entity to the diagram.
+//
+// Entities are keyed by name. When entities from different packages share a
+// name, all of them are keyed by their [Entity.QualifiedName] instead, and
+// relationships already pointing at the entity's bare name follow it. An
+// entity with the same qualified name as an existing one does not replace
+// it: both are kept, the new one under a numbered key such as User_2, and
+// [Diagram.Validate] reports the duplicate.
 func (d *Diagram) AddEntity(entity *Entity) *Diagram {
-	d.Entities[entity.Name] = entity
+	key := d.entityKey(entity)
+	if existing, ok := d.Entities[key]; ok && existing != entity {
+		key = d.duplicateKey(key)
+	}
+	d.Entities[key] = entity
 	return d
 }
 
@@ -54,6 +65,32 @@ func (e *Entity) AddAttribute(attr *Attribute) *Entity {
 	return e
 }
 
+// AddKey adds a key group to the entity.
+func (e *Entity) AddKey(key *Key) *Entity {
+	e.Keys = append(e.Keys, key)
+	return e
+}
+
+// NewKey creates a key of the given type over the named attributes.
+func NewKey(name string, keyType KeyType, attributes ...string) *Key {
+	return &Key{
+		Name:       name,
+		Type:       keyType,
+		Attributes: attributes,
+	}
+}
+
+// WithReference sets the entity a foreign key refers to and, optionally,
+// the referenced attributes. Without attributes the key refers to the
+// entity's primary key.
+func (k *Key) WithReference(entity string, attributes ...string) *Key {
+	k.Reference = &KeyReference{
+		Entity:     entity,
+		Attributes: attributes,
+	}
+	return k
+}
+
 // NewAttribute creates a new attribute.
 func NewAttribute(name, attrType string) *Attribute {
 	return &Attribute{
@@ -123,3 +160,24 @@ func (r *Relationship) WithNote(note string) *Relationship {
 	r.Note = &note
 	return r
 }
+
+// WithAttributes records the attributes of the From and To entities the
+// relationship joins, such as a foreign key and the primary key it refers to.
+func (r *Relationship) WithAttributes(from, to []string) *Relationship {
+	r.FromAttributes = from
+	r.ToAttributes = to
+	return r
+}
+
+// WithFromParticipation sets how many From entities take part in the
+// relationship.
+func (r *Relationship) WithFromParticipation(p Participation) *Relationship {
+	r.FromParticipation = &p
+	return r
+}
+
+// WithToParticipation sets how many To entities one From entity relates to.
+func (r *Relationship) WithToParticipation(p Participation) *Relationship {
+	r.ToParticipation = &p
+	return r
+}
@@ -31,6 +31,36 @@ func TestBuilderMethods(t *testing.T) {
 		}
 	})
 
+	t.Run("Entity.AddKey", func(t *testing.T) {
+		key := NewKey("fk_order", ForeignKey, "TenantID", "OrderID").WithReference("Order", "TenantID", "ID")
+		entity := NewEntity("LineItem").AddKey(key)
+		if len(entity.Keys) != 1 || entity.Keys[0] != key {
+			t.Fatalf("AddKey() keys = %v, want [%v]", entity.Keys, key)
+		}
+		if key.Reference == nil || key.Reference.Entity != "Order" || len(key.Reference.Attributes) != 2 {
+			t.Errorf("WithReference() = %+v, want Order(TenantID, ID)", key.Reference)
+		}
+	})
+
+	t.Run("Relationship.WithAttributes", func(t *testing.T) {
+		rel := NewRelationship("Order", "User", "UserID", ManyToOne).WithAttributes([]string{"UserID"}, []string{"ID"})
+		if len(rel.FromAttributes) != 1 || rel.FromAttributes[0] != "UserID" || len(rel.ToAttributes) != 1 || rel.ToAttributes[0] != "ID" {
+			t.Errorf("WithAttributes() = %v, %v, want [UserID], [ID]", rel.FromAttributes, rel.ToAttributes)
+		}
+	})
+
+	t.Run("Relationship.WithParticipation", func(t *testing.T) {
+		rel := NewRelationship("User", "Profile", "Profile", OneToOne).
+			WithFromParticipation(ExactlyOne).
+			WithToParticipation(ZeroOrOne)
+		if rel.FromParticipation == nil || *rel.FromParticipation != ExactlyOne {
+			t.Errorf("WithFromParticipation() = %v, want %v", rel.FromParticipation, ExactlyOne)
+		}
+		if rel.ToParticipation == nil || *rel.ToParticipation != ZeroOrOne {
+			t.Errorf("WithToParticipation() = %v, want %v", rel.ToParticipation, ZeroOrOne)
+		}
+	})
+
 	t.Run("Relationship.WithLabel", func(t *testing.T) {
 		label := customLabel
 		rel := NewRelationship("User", "Post", "Posts", OneToMany).WithLabel(label)
@@ -0,0 +1,228 @@
+// Command erd generates entity relationship diagrams from Go packages.
+//
+// Usage:
+//
+//	erd [flags] [packages]
+//
+// The packages are loaded statically with [erd.FromPackages] and default to
+// the package in the current directory. With -input, a diagram previously
+// written with -format json is read instead. The diagram is validated and
+// rendered to standard output, or to the file named by -o, which is only
+// replaced once rendering succeeds:
+//
+//	//go:generate go run github.com/zoobzio/erd/cmd/erd -format mermaid -o erd.mmd ./models
+//
+// Flags:
+//
+//	-format       output format: mermaid, dot, plantuml, dbml, sql or json (default mermaid)
+//	-dialect      SQL dialect for -format sql: postgres, mysql or sqlite (default postgres)
+//	-o            write output to file instead of stdout
+//	-input        read a JSON diagram from file ("-" for stdin) instead of loading packages
+//	-title        diagram title (defaults to the package names)
+//	-description  diagram description
+//	-include      only keep entities matching glob (repeatable)
+//	-exclude      drop entities matching glob (repeatable)
+//	-group        group entities by package (DOT clusters, PlantUML packages, DBML table groups)
+//	-infer-fks    link attributes named after an entity, such as UserID, to its primary key (not with -input)
+//	-strict       fail when the diagram has validation errors
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/zoobzio/erd"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "erd: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// config holds the parsed command-line flags.
+type config struct {
+	format      string
+	dialect     string
+	output      string
+	input       string
+	title       string
+	description string
+	include     globList
+	exclude     globList
+	patterns    []string
+	strict      bool
+	group       bool
+	inferFKs    bool
+}
+
+// globList is a repeatable flag of entity name globs.
+type globList []string
+
+// String implements flag.Value.
+func (g *globList) String() string {
+	return strings.Join(*g, ",")
+}
+
+// Set implements flag.Value, accepting comma-separated globs.
+func (g *globList) Set(value string) error {
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+		*g = append(*g, pattern)
+	}
+	return nil
+}
+
+// run executes the command with the given arguments.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	cfg, err := parseFlags(args, stderr)
+	if err != nil {
+		return err
+	}
+
+	diagram, err := load(cfg, stdin)
+	if err != nil {
+		return err
+	}
+
+	if cfg.title != "" {
+		diagram.Title = cfg.title
+	}
+	if cfg.description != "" {
+		diagram.WithDescription(cfg.description)
+	}
+	diagram, err = diagram.Filter(erd.FilterSpec{Include: cfg.include, Exclude: cfg.exclude})
+	if err != nil {
+		return err
+	}
+
+	if errs := diagram.Validate(); len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Fprintf(stderr, "validation: %s\n", e.Error())
+		}
+		if cfg.strict {
+			return fmt.Errorf("diagram has %d validation error(s)", len(errs))
+		}
+	}
+
+	format := erd.Format(cfg.format)
+	opts := erd.RenderOptions{GroupByPackage: cfg.group, Dialect: erd.Dialect(cfg.dialect)}
+	if cfg.output == "" {
+		return erd.RenderWith(stdout, diagram, format, opts)
+	}
+
+	return writeFile(cfg.output, func(w io.Writer) error {
+		return erd.RenderWith(w, diagram, format, opts)
+	})
+}
+
+// writeFile writes a file through a temporary file in the same directory,
+// renamed over name only once write succeeds, so a failed render leaves the
+// previous output in place. A replaced file keeps its mode; new files are
+// created readable by others.
+func writeFile(name string, write func(io.Writer) error) (err error) {
+	mode := os.FileMode(0o644)
+	if info, statErr := os.Stat(name); statErr == nil {
+		mode = info.Mode().Perm()
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	if err = write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(f.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+// parseFlags parses command-line arguments into a config.
+func parseFlags(args []string, stderr io.Writer) (*config, error) {
+	cfg := &config{}
+
+	fs := flag.NewFlagSet("erd", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: erd [flags] [packages]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&cfg.format, "format", "mermaid", "output format: mermaid, dot, plantuml, dbml, sql or json")
+	fs.StringVar(&cfg.dialect, "dialect", string(erd.PostgreSQL), "SQL dialect for -format sql: postgres, mysql or sqlite")
+	fs.StringVar(&cfg.output, "o", "", "write output to `file` instead of stdout")
+	fs.StringVar(&cfg.input, "input", "", "read a JSON diagram from `file` (\"-\" for stdin) instead of loading packages")
+	fs.StringVar(&cfg.title, "title", "", "diagram title (defaults to the package names)")
+	fs.StringVar(&cfg.description, "description", "", "diagram description")
+	fs.Var(&cfg.include, "include", "only keep entities matching `glob` (repeatable, comma-separated)")
+	fs.Var(&cfg.exclude, "exclude", "drop entities matching `glob` (repeatable, comma-separated)")
+	fs.BoolVar(&cfg.group, "group", false, "group entities by package")
+	fs.BoolVar(&cfg.inferFKs, "infer-fks", false, "link attributes named after an entity, such as UserID, to its primary key")
+	fs.BoolVar(&cfg.strict, "strict", false, "fail when the diagram has validation errors")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(erd.Formats(), erd.Format(cfg.format)) {
+		return nil, fmt.Errorf("unknown format %q", cfg.format)
+	}
+
+	cfg.patterns = fs.Args()
+	if cfg.input != "" && len(cfg.patterns) > 0 {
+		return nil, errors.New("-input cannot be combined with package patterns")
+	}
+	if cfg.input != "" && cfg.inferFKs {
+		return nil, errors.New("-infer-fks cannot be combined with -input")
+	}
+	if cfg.input == "" && len(cfg.patterns) == 0 {
+		cfg.patterns = []string{"."}
+	}
+
+	return cfg, nil
+}
+
+// load builds the diagram from the JSON input or the package patterns.
+func load(cfg *config, stdin io.Reader) (*erd.Diagram, error) {
+	switch cfg.input {
+	case "":
+		return erd.FromPackagesWith(erd.SchemaOptions{InferForeignKeys: cfg.inferFKs}, cfg.patterns...)
+	case "-":
+		return erd.ReadJSON(stdin)
+	default:
+		f, err := os.Open(cfg.input) //nolint:gosec // the input path is chosen by the user
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return erd.ReadJSON(f)
+	}
+}
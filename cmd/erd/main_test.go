@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPackage = "../../testdata/models"
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "mermaid by default",
+			args: []string{testPackage},
+			want: []string{"erDiagram\n", "User {", "User ||--o{ Order : Orders"},
+		},
+		{
+			name: "dot",
+			args: []string{"-format", "dot", "-title", "Shop", testPackage},
+			want: []string{"digraph ERD {", `label="Shop";`},
+		},
+		{
+			name: "plantuml",
+			args: []string{"-format", "plantuml", testPackage},
+			want: []string{"@startuml", "@enduml"},
+		},
+		{
+			name: "dbml",
+			args: []string{"-format", "dbml", "-description", "Shop domain", testPackage},
+			want: []string{"Table User {", "Note: 'Shop domain'"},
+		},
+		{
+			name: "sql",
+			args: []string{"-format", "sql", "-dialect", "mysql", testPackage},
+			want: []string{"CREATE TABLE `User`"},
+		},
+		{
+			name: "group",
+			args: []string{"-format", "dot", "-group", testPackage},
+			want: []string{"subgraph cluster_github_com_zoobzio_erd_testdata_models {", `label="github.com/zoobzio/erd/testdata/models";`},
+		},
+		{
+			name: "infer foreign keys",
+			args: []string{"-format", "json", "-infer-fks", testPackage},
+			want: []string{`"from_attributes": [`, `"to_attributes": [`},
+		},
+		{
+			name:    "no inferred foreign keys by default",
+			args:    []string{"-format", "json", testPackage},
+			notWant: []string{`"from_attributes"`},
+		},
+		{
+			name:    "include",
+			args:    []string{"-include", "User,Order", testPackage},
+			want:    []string{"User {", "Order {", "User ||--o{ Order : Orders"},
+			notWant: []string{"Profile {", "Company {", ": Profile"},
+		},
+		{
+			name:    "exclude",
+			args:    []string{"-exclude", "*Item", "-exclude", "Comp*", testPackage},
+			want:    []string{"User {", "Order {"},
+			notWant: []string{"LineItem {", "Company {", ": Lines"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if err := run(tt.args, nil, &stdout, &stderr); err != nil {
+				t.Fatalf("run() unexpected error: %v (stderr: %s)", err, stderr.String())
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(stdout.String(), w) {
+					t.Errorf("output should contain %q, got:\n%s", w, stdout.String())
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(stdout.String(), w) {
+					t.Errorf("output should not contain %q, got:\n%s", w, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRun_OutputFile(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "erd.mmd")
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-o", out, testPackage}, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("run() unexpected error: %v", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected no stdout when writing to a file, got %q", stdout.String())
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "erDiagram\n") {
+		t.Errorf("expected mermaid output in file, got %q", data)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "erd.mmd")
+	if err := os.WriteFile(out, []byte("previous\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed write keeps the previous output and removes its temporary file
+	err := writeFile(out, func(w io.Writer) error {
+		if _, err := io.WriteString(w, "partial"); err != nil {
+			return err
+		}
+		return errors.New("render failed")
+	})
+	if err == nil {
+		t.Fatal("writeFile() expected error")
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	if string(data) != "previous\n" {
+		t.Errorf("failed write should keep the previous output, got %q", data)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("failed write should remove its temporary file, got %d files", len(entries))
+	}
+
+	// A successful write replaces it and keeps its mode
+	write := func(w io.Writer) error {
+		_, err := io.WriteString(w, "next\n")
+		return err
+	}
+	if err := writeFile(out, write); err != nil {
+		t.Fatalf("writeFile() unexpected error: %v", err)
+	}
+	if data, err := os.ReadFile(out); err != nil || string(data) != "next\n" {
+		t.Errorf("output = %q, %v, want %q", data, err, "next\n")
+	}
+	if info, err := os.Stat(out); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0o600 {
+		t.Errorf("replaced output mode = %v, want %v", info.Mode().Perm(), os.FileMode(0o600))
+	}
+
+	// A new file is readable by others
+	created := filepath.Join(dir, "erd.dot")
+	if err := writeFile(created, write); err != nil {
+		t.Fatalf("writeFile() unexpected error: %v", err)
+	}
+	if info, err := os.Stat(created); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0o644 {
+		t.Errorf("new output mode = %v, want %v", info.Mode().Perm(), os.FileMode(0o644))
+	}
+}
+
+func TestRun_Strict(t *testing.T) {
+	// Excluding every entity leaves an invalid diagram
+	args := []string{"-exclude", "*", testPackage}
+
+	var stdout, stderr bytes.Buffer
+	if err := run(args, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("run() without -strict should succeed, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "validation: Entities") {
+		t.Errorf("expected validation warning on stderr, got %q", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if err := run(append([]string{"-strict"}, args...), nil, &stdout, &stderr); err == nil {
+		t.Error("run() with -strict should fail on validation errors")
+	}
+}
+
+func TestRun_Duplicates(t *testing.T) {
+	input := `{"version": 1, "title": "Test", "relationships": [], "entities": {
+		"User": {"name": "User", "attributes": [{"name": "ID", "type": "string"}]},
+		"User_2": {"name": "User", "attributes": [{"name": "Email", "type": "string"}]}}}`
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-input", "-", "-include", "User*"}, strings.NewReader(input), &stdout, &stderr); err != nil {
+		t.Fatalf("run() unexpected error: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "validation: Entity[User_2]: duplicate entity 'User'") {
+		t.Errorf("expected duplicate warning on stderr, got %q", stderr.String())
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown format", []string{"-format", "svg", testPackage}},
+		{"unknown dialect", []string{"-format", "sql", "-dialect", "oracle", testPackage}},
+		{"invalid glob", []string{"-include", "[", testPackage}},
+		{"unknown flag", []string{"-nope"}},
+		{"missing package", []string{"../../testdata/does-not-exist"}},
+		{"missing input", []string{"-input", "../../testdata/does-not-exist.json"}},
+		{"input with packages", []string{"-input", "-", testPackage}},
+		{"input with inferred foreign keys", []string{"-input", "-", "-infer-fks"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if err := run(tt.args, nil, &stdout, &stderr); err == nil {
+				t.Error("run() expected error")
+			}
+		})
+	}
+}
+
+func TestRun_JSON(t *testing.T) {
+	// Write the package diagram as JSON, then render it from the JSON file
+	file := filepath.Join(t.TempDir(), "erd.json")
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-format", "json", "-o", file, testPackage}, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("run() unexpected error: %v", err)
+	}
+
+	if err := run([]string{testPackage}, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("run() unexpected error: %v", err)
+	}
+	want := stdout.String()
+
+	stdout.Reset()
+	if err := run([]string{"-input", file}, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("run() with -input unexpected error: %v", err)
+	}
+	if stdout.String() != want {
+		t.Errorf("diagram read from JSON renders differently:\ngot:\n%s\nwant:\n%s", stdout.String(), want)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	stdout.Reset()
+	if err := run([]string{"-input", "-", "-format", "json"}, bytes.NewReader(data), &stdout, &stderr); err != nil {
+		t.Fatalf("run() with stdin input unexpected error: %v", err)
+	}
+	if stdout.String() != string(data) {
+		t.Error("JSON should round-trip through stdin unchanged")
+	}
+}
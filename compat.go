@@ -0,0 +1,273 @@
+package erd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity classifies the impact of a change on consumers of a diagram.
+type Severity string
+
+// Severity constants.
+const (
+	Breaking Severity = "breaking"
+	Safe     Severity = "safe"
+)
+
+// CompatibilityPolicy adjusts how [CheckCompatibility] classifies changes.
+// The zero value applies the default rules.
+type CompatibilityPolicy struct {
+	// Overrides replaces the classification of every change of a kind,
+	// e.g. treating EntityAdded as breaking for closed contracts.
+	Overrides map[ChangeKind]Severity
+}
+
+// Finding is a classified change.
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Reason   string   `json:"reason"`
+	Change   Change   `json:"change"`
+}
+
+// CompatibilityReport lists the classified changes between two diagrams.
+type CompatibilityReport struct {
+	Findings []Finding `json:"findings"`
+}
+
+// CheckCompatibility classifies the changes from before to after as
+// breaking or safe for consumers of the before diagram.
+//
+// By default, removing or renaming entities, attributes or relationships is
+// breaking, as are making an attribute non-nullable, narrowing its type
+// (e.g. int64 to int32 or *string to string), adding, removing or changing
+// a primary key, adding or changing a unique or foreign key and
+// tightening a relationship's cardinality (e.g. one-to-many to one-to-one) or
+// participation (e.g. 0..N to 1..N) and changing the attributes it joins.
+// Additions, widenings and loosenings are safe. Incompatible type changes are
+// breaking.
+func CheckCompatibility(before, after *Diagram, policy CompatibilityPolicy) *CompatibilityReport {
+	report := &CompatibilityReport{Findings: []Finding{}}
+
+	for _, change := range Diff(before, after).Changes {
+		severity, reason := classifyChange(change)
+		if override, ok := policy.Overrides[change.Kind]; ok {
+			severity, reason = override, "classified by policy"
+		}
+		report.Findings = append(report.Findings, Finding{
+			Severity: severity,
+			Reason:   reason,
+			Change:   change,
+		})
+	}
+
+	return report
+}
+
+// Compatible reports whether the report has no breaking findings.
+func (r *CompatibilityReport) Compatible() bool {
+	return len(r.Breaking()) == 0
+}
+
+// Breaking returns the breaking findings.
+func (r *CompatibilityReport) Breaking() []Finding {
+	var breaking []Finding
+	for _, f := range r.Findings {
+		if f.Severity == Breaking {
+			breaking = append(breaking, f)
+		}
+	}
+	return breaking
+}
+
+// String renders the report as human-readable text, one finding per line.
+func (r *CompatibilityReport) String() string {
+	if len(r.Findings) == 0 {
+		return "No changes\n"
+	}
+
+	var sb strings.Builder
+	for _, f := range r.Findings {
+		sb.WriteString(f.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// String describes the finding in a single line.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s (%s)", strings.ToUpper(string(f.Severity)), f.Change, f.Reason)
+}
+
+// classifyChange applies the default compatibility rules to a change.
+func classifyChange(c Change) (Severity, string) {
+	switch c.Kind {
+	case EntityAdded:
+		return Safe, "entity added"
+	case EntityRemoved:
+		return Breaking, "entity removed"
+	case EntityRenamed:
+		return Breaking, "entity renamed"
+	case AttributeAdded:
+		return Safe, "attribute added"
+	case AttributeRemoved:
+		return Breaking, "attribute removed"
+	case AttributeTypeChanged:
+		if typeWidens(c.Old, c.New) {
+			return Safe, "type widened"
+		}
+		if typeWidens(c.New, c.Old) {
+			return Breaking, "type narrowed"
+		}
+		return Breaking, "incompatible type change"
+	case AttributeNullabilityChanged:
+		if c.New == nullability(false) {
+			return Breaking, "nullable attribute became required"
+		}
+		return Safe, "attribute became nullable"
+	case AttributeKeyChanged:
+		switch {
+		case c.Old == string(PrimaryKey) || c.New == string(PrimaryKey):
+			return Breaking, "primary key changed"
+		case c.New == string(UniqueKey):
+			return Breaking, "uniqueness constraint added"
+		case c.New == string(ForeignKey):
+			return Breaking, "foreign key added"
+		default:
+			return Safe, "key constraint relaxed"
+		}
+	case KeyAdded, KeyRemoved, KeyChanged:
+		return classifyKeyChange(c)
+	case RelationshipAdded:
+		return Safe, "relationship added"
+	case RelationshipRemoved:
+		return Breaking, "relationship removed"
+	case RelationshipCardinalityChanged:
+		if cardinalityTightens(Cardinality(c.Old), Cardinality(c.New)) {
+			return Breaking, "cardinality tightened"
+		}
+		return Safe, "cardinality loosened"
+	case RelationshipParticipationChanged:
+		oldFrom, oldTo, _ := strings.Cut(c.Old, ", ")
+		newFrom, newTo, _ := strings.Cut(c.New, ", ")
+		if participationTightens(Participation(oldFrom), Participation(newFrom)) ||
+			participationTightens(Participation(oldTo), Participation(newTo)) {
+			return Breaking, "participation tightened"
+		}
+		return Safe, "participation loosened"
+	case RelationshipAttributesChanged:
+		if c.Old == "" {
+			return Safe, "joined attributes recorded"
+		}
+		return Breaking, "joined attributes changed"
+	default:
+		return Breaking, "unknown change"
+	}
+}
+
+// classifyKeyChange applies the default compatibility rules to a key group
+// change. Like key changes on attributes, primary key changes and new or
+// changed unique keys are breaking; so are new foreign keys and foreign keys
+// that refer to different attributes. Only removed keys are relaxed.
+func classifyKeyChange(c Change) (Severity, string) {
+	oldType, newType := keyDescriptionType(c.Old), keyDescriptionType(c.New)
+	switch {
+	case oldType == string(PrimaryKey) || newType == string(PrimaryKey):
+		return Breaking, "primary key changed"
+	case newType == string(UniqueKey) && c.Kind == KeyAdded:
+		return Breaking, "uniqueness constraint added"
+	case newType == string(UniqueKey):
+		return Breaking, "uniqueness constraint changed"
+	case c.Kind == KeyAdded:
+		return Breaking, "foreign key added"
+	case c.Kind == KeyChanged:
+		return Breaking, "foreign key changed"
+	default:
+		return Safe, "key constraint relaxed"
+	}
+}
+
+// numericRanks orders Go numeric types by the values they can hold within
+// each family. Platform-sized int and uint are treated as 64-bit.
+var numericRanks = map[string]struct {
+	family string
+	rank   int
+}{
+	"int8":    {"int", 1},
+	"int16":   {"int", 2},
+	"int32":   {"int", 3},
+	"rune":    {"int", 3},
+	"int":     {"int", 4},
+	"int64":   {"int", 4},
+	"uint8":   {"uint", 1},
+	"byte":    {"uint", 1},
+	"uint16":  {"uint", 2},
+	"uint32":  {"uint", 3},
+	"uint":    {"uint", 4},
+	"uint64":  {"uint", 4},
+	"float32": {"float", 1},
+	"float64": {"float", 2},
+}
+
+// typeWidens reports whether every value of type from fits in type to:
+// adding a pointer makes a type nullable, and numeric types may grow
+// within their family.
+func typeWidens(from, to string) bool {
+	if strings.HasPrefix(to, "*") {
+		to = strings.TrimPrefix(to, "*")
+		from = strings.TrimPrefix(from, "*")
+	} else if strings.HasPrefix(from, "*") {
+		return false
+	}
+	if from == to {
+		return true
+	}
+
+	f, okFrom := numericRanks[from]
+	t, okTo := numericRanks[to]
+	return okFrom && okTo && f.family == t.family && f.rank <= t.rank
+}
+
+// cardinalityTightens reports whether either end of a relationship goes
+// from many to one.
+func cardinalityTightens(from, to Cardinality) bool {
+	fromLeft, fromRight := cardinalityEnds(from)
+	toLeft, toRight := cardinalityEnds(to)
+	return (fromLeft && !toLeft) || (fromRight && !toRight)
+}
+
+// participationBounds returns the minimum of a participation and whether it
+// allows many. Unknown participation is treated as exactly one.
+func participationBounds(p Participation) (mandatory, many bool) {
+	switch p {
+	case ZeroOrOne:
+		return false, false
+	case ZeroOrMore:
+		return false, true
+	case OneOrMore:
+		return true, true
+	default:
+		return true, false
+	}
+}
+
+// participationTightens reports whether an end goes from optional to
+// mandatory or from many to at most one, e.g. 0..N to 1..N.
+func participationTightens(from, to Participation) bool {
+	fromMandatory, fromMany := participationBounds(from)
+	toMandatory, toMany := participationBounds(to)
+	return (!fromMandatory && toMandatory) || (fromMany && !toMany)
+}
+
+// cardinalityEnds reports whether each end of a relationship allows many.
+func cardinalityEnds(c Cardinality) (fromMany, toMany bool) {
+	switch c {
+	case OneToMany:
+		return false, true
+	case ManyToOne:
+		return true, false
+	case ManyToMany:
+		return true, true
+	default:
+		return false, false
+	}
+}
@@ -0,0 +1,369 @@
+package erd
+
+import (
+	"testing"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(d *Diagram)
+		severity Severity
+		reason   string
+	}{
+		{
+			name:     "entity added",
+			modify:   func(d *Diagram) { d.AddEntity(NewEntity("Shop").AddAttribute(NewAttribute("ID", "string"))) },
+			severity: Safe,
+			reason:   "entity added",
+		},
+		{
+			name:     "attribute added",
+			modify:   func(d *Diagram) { d.Entities["User"].AddAttribute(NewAttribute("Phone", "string")) },
+			severity: Safe,
+			reason:   "attribute added",
+		},
+		{
+			name: "attribute removed",
+			modify: func(d *Diagram) {
+				d.Entities["User"].Attributes = d.Entities["User"].Attributes[:2]
+			},
+			severity: Breaking,
+			reason:   "attribute removed",
+		},
+		{
+			name:     "nullable to required",
+			modify:   func(d *Diagram) { d.Entities["User"].Attributes[1].Nullable = false },
+			severity: Breaking,
+			reason:   "nullable attribute became required",
+		},
+		{
+			name:     "type narrowed",
+			modify:   func(d *Diagram) { d.Entities["User"].Attributes[2].Type = "int16" },
+			severity: Breaking,
+			reason:   "type narrowed",
+		},
+		{
+			name:     "type widened",
+			modify:   func(d *Diagram) { d.Entities["User"].Attributes[2].Type = "int64" },
+			severity: Safe,
+			reason:   "type widened",
+		},
+		{
+			name:     "incompatible type",
+			modify:   func(d *Diagram) { d.Entities["User"].Attributes[2].Type = "string" },
+			severity: Breaking,
+			reason:   "incompatible type change",
+		},
+		{
+			name:     "primary key changed",
+			modify:   func(d *Diagram) { d.Entities["User"].Attributes[0].Key = nil },
+			severity: Breaking,
+			reason:   "primary key changed",
+		},
+		{
+			name:     "unique key added",
+			modify:   func(d *Diagram) { d.Entities["User"].Attributes[2].WithUnique() },
+			severity: Breaking,
+			reason:   "uniqueness constraint added",
+		},
+		{
+			name:     "foreign key added",
+			modify:   func(d *Diagram) { d.Entities["User"].Attributes[2].WithForeignKey() },
+			severity: Breaking,
+			reason:   "foreign key added",
+		},
+		{
+			name:     "foreign key removed",
+			modify:   func(d *Diagram) { d.Entities["Order"].Attributes[1].Key = nil },
+			severity: Safe,
+			reason:   "key constraint relaxed",
+		},
+		{
+			name:     "relationship removed",
+			modify:   func(d *Diagram) { d.Relationships = nil },
+			severity: Breaking,
+			reason:   "relationship removed",
+		},
+		{
+			name:     "relationship added",
+			modify:   func(d *Diagram) { d.AddRelationship(NewRelationship("Order", "User", "User", ManyToOne)) },
+			severity: Safe,
+			reason:   "relationship added",
+		},
+		{
+			name:     "cardinality tightened",
+			modify:   func(d *Diagram) { d.Relationships[0].Cardinality = OneToOne },
+			severity: Breaking,
+			reason:   "cardinality tightened",
+		},
+		{
+			name:     "cardinality loosened",
+			modify:   func(d *Diagram) { d.Relationships[0].Cardinality = ManyToMany },
+			severity: Safe,
+			reason:   "cardinality loosened",
+		},
+		{
+			name: "participation tightened",
+			modify: func(d *Diagram) {
+				d.Relationships[0].WithFromParticipation(ZeroOrMore).WithToParticipation(OneOrMore)
+			},
+			severity: Breaking,
+			reason:   "participation tightened",
+		},
+		{
+			name:     "participation loosened",
+			modify:   func(d *Diagram) { d.Relationships[0].WithFromParticipation(ZeroOrMore).WithToParticipation(ZeroOrMore) },
+			severity: Safe,
+			reason:   "participation loosened",
+		},
+		{
+			name: "joined attributes recorded",
+			modify: func(d *Diagram) {
+				d.Relationships[0].WithAttributes([]string{"ID"}, []string{"UserID"})
+			},
+			severity: Safe,
+			reason:   "joined attributes recorded",
+		},
+		{
+			name: "entity removed",
+			modify: func(d *Diagram) {
+				delete(d.Entities, "Order")
+				d.Relationships = nil
+			},
+			severity: Breaking,
+			reason:   "entity removed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := testCompatBase()
+			tt.modify(after)
+
+			report := CheckCompatibility(testCompatBase(), after, CompatibilityPolicy{})
+			if len(report.Findings) == 0 {
+				t.Fatal("CheckCompatibility() expected findings")
+			}
+			f := report.Findings[0]
+			if f.Severity != tt.severity || f.Reason != tt.reason {
+				t.Errorf("finding = %s %q, want %s %q", f.Severity, f.Reason, tt.severity, tt.reason)
+			}
+			if report.Compatible() != (tt.severity == Safe) {
+				t.Errorf("Compatible() = %v, want %v\n%s", report.Compatible(), tt.severity == Safe, report)
+			}
+		})
+	}
+}
+
+func TestCheckCompatibility_Keys(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(d *Diagram)
+		severity Severity
+		reason   string
+	}{
+		{
+			name:     "composite primary key changed",
+			modify:   func(d *Diagram) { d.Entities["LineItem"].Keys[0].Attributes = []string{"OrderID", "SKU"} },
+			severity: Breaking,
+			reason:   "primary key changed",
+		},
+		{
+			name:     "composite primary key removed",
+			modify:   func(d *Diagram) { d.Entities["LineItem"].Keys = d.Entities["LineItem"].Keys[1:] },
+			severity: Breaking,
+			reason:   "primary key changed",
+		},
+		{
+			name:     "unique key added",
+			modify:   func(d *Diagram) { d.Entities["LineItem"].AddKey(NewKey("uk_sku", UniqueKey, "OrderID", "SKU")) },
+			severity: Breaking,
+			reason:   "uniqueness constraint added",
+		},
+		{
+			name:     "unique key changed",
+			modify:   func(d *Diagram) { d.Entities["Order"].Keys[1].Attributes = []string{"Number"} },
+			severity: Breaking,
+			reason:   "uniqueness constraint changed",
+		},
+		{
+			name:     "unique key removed",
+			modify:   func(d *Diagram) { d.Entities["Order"].Keys = d.Entities["Order"].Keys[:1] },
+			severity: Safe,
+			reason:   "key constraint relaxed",
+		},
+		{
+			name: "foreign key added",
+			modify: func(d *Diagram) {
+				d.Entities["LineItem"].AddKey(NewKey("fk_tenant", ForeignKey, "TenantID").WithReference("Tenant", "ID"))
+			},
+			severity: Breaking,
+			reason:   "foreign key added",
+		},
+		{
+			name:     "foreign key changed",
+			modify:   func(d *Diagram) { d.Entities["LineItem"].Keys[1].Reference.Attributes = []string{"ID", "TenantID"} },
+			severity: Breaking,
+			reason:   "foreign key changed",
+		},
+		{
+			name:     "foreign key removed",
+			modify:   func(d *Diagram) { d.Entities["LineItem"].Keys = d.Entities["LineItem"].Keys[:1] },
+			severity: Safe,
+			reason:   "key constraint relaxed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := testCompositeDiagram()
+			tt.modify(after)
+
+			report := CheckCompatibility(testCompositeDiagram(), after, CompatibilityPolicy{})
+			if len(report.Findings) != 1 {
+				t.Fatalf("CheckCompatibility() expected one finding, got:\n%s", report)
+			}
+			if f := report.Findings[0]; f.Severity != tt.severity || f.Reason != tt.reason {
+				t.Errorf("finding = %s %q, want %s %q", f.Severity, f.Reason, tt.severity, tt.reason)
+			}
+		})
+	}
+}
+
+func TestCheckCompatibility_JoinedAttributes(t *testing.T) {
+	before := testCompatBase()
+	before.Relationships[0].WithAttributes([]string{"ID"}, []string{"UserID"})
+	after := testCompatBase()
+	after.Relationships[0].WithAttributes([]string{"ID"}, []string{"ID"})
+
+	report := CheckCompatibility(before, after, CompatibilityPolicy{})
+	if len(report.Findings) != 1 {
+		t.Fatalf("CheckCompatibility() expected one finding, got:\n%s", report)
+	}
+	if f := report.Findings[0]; f.Severity != Breaking || f.Reason != "joined attributes changed" {
+		t.Errorf("finding = %s %q, want breaking %q", f.Severity, f.Reason, "joined attributes changed")
+	}
+}
+
+func TestParticipationTightens(t *testing.T) {
+	tests := []struct {
+		from, to Participation
+		want     bool
+	}{
+		{ZeroOrMore, OneOrMore, true},
+		{ZeroOrOne, ExactlyOne, true},
+		{ZeroOrMore, ZeroOrOne, true},
+		{OneOrMore, ExactlyOne, true},
+		{OneOrMore, ZeroOrMore, false},
+		{ExactlyOne, ZeroOrOne, false},
+		{ZeroOrOne, ZeroOrMore, false},
+		{ExactlyOne, ExactlyOne, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := participationTightens(tt.from, tt.to); got != tt.want {
+				t.Errorf("participationTightens() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testCompatBase() *Diagram {
+	return NewDiagram("Contract").
+		AddEntity(NewEntity("User").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("Email", "string").WithNullable()).
+			AddAttribute(NewAttribute("Age", "int32"))).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("UserID", "string").WithForeignKey())).
+		AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany))
+}
+
+func TestCheckCompatibility_Policy(t *testing.T) {
+	after := testCompatBase()
+	after.Entities["User"].Attributes = after.Entities["User"].Attributes[:2]
+	after.AddEntity(NewEntity("Shop").AddAttribute(NewAttribute("ID", "string")))
+
+	policy := CompatibilityPolicy{Overrides: map[ChangeKind]Severity{
+		AttributeRemoved: Safe,
+		EntityAdded:      Breaking,
+	}}
+	report := CheckCompatibility(testCompatBase(), after, policy)
+
+	breaking := report.Breaking()
+	if len(breaking) != 1 || breaking[0].Change.Kind != EntityAdded {
+		t.Fatalf("expected only the entity addition to be breaking, got:\n%s", report)
+	}
+	if breaking[0].Reason != "classified by policy" {
+		t.Errorf("Reason = %q, want %q", breaking[0].Reason, "classified by policy")
+	}
+}
+
+func TestCompatibilityReport_String(t *testing.T) {
+	after := testCompatBase()
+	after.Entities["User"].Attributes[1].Nullable = false
+	after.AddEntity(NewEntity("Shop").AddAttribute(NewAttribute("ID", "string")))
+
+	got := CheckCompatibility(testCompatBase(), after, CompatibilityPolicy{}).String()
+	want := "SAFE: Added entity Shop (entity added)\n" +
+		"BREAKING: User.Email became not null (nullable attribute became required)\n"
+	if got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+
+	empty := CheckCompatibility(testCompatBase(), testCompatBase(), CompatibilityPolicy{})
+	if empty.String() != "No changes\n" || !empty.Compatible() {
+		t.Errorf("identical diagrams should be compatible with no changes, got %q", empty.String())
+	}
+}
+
+func TestTypeWidens(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{"string", "string", true},
+		{"string", "*string", true},
+		{"*string", "string", false},
+		{"*int32", "*int64", true},
+		{"int32", "*int64", true},
+		{"int64", "int32", false},
+		{"int", "int64", true},
+		{"uint8", "uint16", true},
+		{"uint32", "int64", false},
+		{"float32", "float64", true},
+		{"float64", "float32", false},
+		{"int", "string", false},
+		{"time.Time", "string", false},
+	}
+
+	for _, tt := range tests {
+		if got := typeWidens(tt.from, tt.to); got != tt.want {
+			t.Errorf("typeWidens(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestCardinalityTightens(t *testing.T) {
+	tests := []struct {
+		from Cardinality
+		to   Cardinality
+		want bool
+	}{
+		{OneToMany, OneToOne, true},
+		{ManyToMany, OneToMany, true},
+		{OneToMany, ManyToOne, true},
+		{OneToOne, OneToMany, false},
+		{ManyToOne, ManyToMany, false},
+	}
+
+	for _, tt := range tests {
+		if got := cardinalityTightens(tt.from, tt.to); got != tt.want {
+			t.Errorf("cardinalityTightens(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+package erd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DatabaseOptions configures live database introspection.
+type DatabaseOptions struct {
+	// Title is the diagram title.
+	Title string
+	// Dialect selects the catalog queries used to read the schema.
+	Dialect Dialect
+	// Schemas limits introspection to the named schemas. When empty,
+	// PostgreSQL reads every non-system schema and MySQL reads the
+	// current database. Ignored for SQLite.
+	Schemas []string
+}
+
+// FromDatabase builds an ERD diagram by introspecting a live database.
+//
+// PostgreSQL and MySQL are read through information_schema; SQLite through
+// sqlite_master and its pragma table functions. Tables, columns and keys are
+// converted exactly as [FromSQL] converts DDL: schemas become
+// [Entity.Package], column comments become attribute notes and foreign key
+// constraints become relationships.
+func FromDatabase(ctx context.Context, db *sql.DB, opts DatabaseOptions) (*Diagram, error) {
+	var (
+		tables []*sqlTable
+		err    error
+	)
+
+	switch opts.Dialect {
+	case PostgreSQL:
+		tables, err = introspectInformationSchema(ctx, db, postgresQueries, opts.Schemas)
+	case MySQL:
+		tables, err = introspectInformationSchema(ctx, db, mysqlQueries, opts.Schemas)
+	case SQLite:
+		tables, err = introspectSQLite(ctx, db)
+	default:
+		return nil, fmt.Errorf("unsupported SQL dialect: %q", opts.Dialect)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return diagramFromSQLTables(opts.Title, tables, opts.Dialect), nil
+}
+
+// catalogQueries holds the information_schema queries for a dialect.
+// Each query returns rows for every user schema, ordered for stable output.
+type catalogQueries struct {
+	// tables: schema, table, comment
+	tables string
+	// columns: schema, table, column, type, is_nullable, comment
+	columns string
+	// constraints: schema, table, constraint type, constraint name, column,
+	// referenced schema, referenced table, referenced column
+	constraints string
+	// defaultSchemas, when set, returns the schemas read if none are requested
+	defaultSchemas string
+}
+
+// postgresQueries reads PostgreSQL's information_schema, with comments
+// taken from pg_catalog.
+var postgresQueries = catalogQueries{
+	tables: `
+SELECT t.table_schema, t.table_name,
+       obj_description(format('%I.%I', t.table_schema, t.table_name)::regclass, 'pg_class')
+FROM information_schema.tables t
+WHERE t.table_type = 'BASE TABLE'
+  AND t.table_schema NOT IN ('pg_catalog', 'information_schema')
+  AND t.table_schema NOT LIKE 'pg_toast%'
+ORDER BY t.table_schema, t.table_name`,
+	columns: `
+SELECT c.table_schema, c.table_name, c.column_name,
+       CASE
+           WHEN c.data_type = 'ARRAY' THEN ltrim(c.udt_name, '_') || '[]'
+           WHEN c.data_type = 'USER-DEFINED' THEN c.udt_name
+           ELSE c.data_type
+       END,
+       c.is_nullable,
+       col_description(format('%I.%I', c.table_schema, c.table_name)::regclass, c.ordinal_position)
+FROM information_schema.columns c
+WHERE c.table_schema NOT IN ('pg_catalog', 'information_schema')
+  AND c.table_schema NOT LIKE 'pg_toast%'
+ORDER BY c.table_schema, c.table_name, c.ordinal_position`,
+	constraints: `
+SELECT kcu.table_schema, kcu.table_name, tc.constraint_type, tc.constraint_name, kcu.column_name,
+       COALESCE(rk.table_schema, ''), COALESCE(rk.table_name, ''), COALESCE(rk.column_name, '')
+FROM information_schema.table_constraints tc
+JOIN information_schema.key_column_usage kcu
+  ON kcu.constraint_schema = tc.constraint_schema
+ AND kcu.constraint_name = tc.constraint_name
+ AND kcu.table_name = tc.table_name
+LEFT JOIN information_schema.referential_constraints rc
+  ON rc.constraint_schema = tc.constraint_schema
+ AND rc.constraint_name = tc.constraint_name
+LEFT JOIN information_schema.key_column_usage rk
+  ON rk.constraint_schema = rc.unique_constraint_schema
+ AND rk.constraint_name = rc.unique_constraint_name
+ AND rk.ordinal_position = kcu.position_in_unique_constraint
+WHERE tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+  AND tc.table_schema NOT IN ('pg_catalog', 'information_schema')
+ORDER BY kcu.table_schema, kcu.table_name, tc.constraint_name, kcu.ordinal_position`,
+}
+
+// mysqlQueries reads MySQL's information_schema.
+var mysqlQueries = catalogQueries{
+	tables: `
+SELECT table_schema, table_name, NULLIF(table_comment, '')
+FROM information_schema.tables
+WHERE table_type = 'BASE TABLE'
+  AND table_schema NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+ORDER BY table_schema, table_name`,
+	columns: `
+SELECT table_schema, table_name, column_name, column_type, is_nullable, NULLIF(column_comment, '')
+FROM information_schema.columns
+WHERE table_schema NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+ORDER BY table_schema, table_name, ordinal_position`,
+	constraints: `
+SELECT kcu.table_schema, kcu.table_name, tc.constraint_type, tc.constraint_name, kcu.column_name,
+       COALESCE(kcu.referenced_table_schema, ''), COALESCE(kcu.referenced_table_name, ''),
+       COALESCE(kcu.referenced_column_name, '')
+FROM information_schema.table_constraints tc
+JOIN information_schema.key_column_usage kcu
+  ON kcu.constraint_schema = tc.constraint_schema
+ AND kcu.constraint_name = tc.constraint_name
+ AND kcu.table_name = tc.table_name
+WHERE tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+  AND tc.table_schema NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+ORDER BY kcu.table_schema, kcu.table_name, tc.constraint_name, kcu.ordinal_position`,
+	defaultSchemas: `SELECT DATABASE()`,
+}
+
+// introspectInformationSchema reads tables, columns and key constraints
+// through information_schema.
+func introspectInformationSchema(ctx context.Context, db *sql.DB, queries catalogQueries, schemas []string) ([]*sqlTable, error) {
+	if len(schemas) == 0 && queries.defaultSchemas != "" {
+		var current sql.NullString
+		if err := db.QueryRowContext(ctx, queries.defaultSchemas).Scan(&current); err != nil {
+			return nil, fmt.Errorf("reading current schema: %w", err)
+		}
+		if current.Valid {
+			schemas = []string{current.String}
+		}
+	}
+	include := func(schema string) bool {
+		return len(schemas) == 0 || containsFold(schemas, schema)
+	}
+
+	tables := make(map[string]*sqlTable)
+	var ordered []*sqlTable
+
+	// Tables
+	if err := queryRows(ctx, db, queries.tables, func(rows *sql.Rows) error {
+		var schema, name string
+		var comment sql.NullString
+		if err := rows.Scan(&schema, &name, &comment); err != nil {
+			return err
+		}
+		if !include(schema) {
+			return nil
+		}
+		table := &sqlTable{Schema: schema, Name: name, Note: nullStringPtr(comment)}
+		tables[schema+"."+name] = table
+		ordered = append(ordered, table)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("reading tables: %w", err)
+	}
+
+	// Columns
+	if err := queryRows(ctx, db, queries.columns, func(rows *sql.Rows) error {
+		var schema, table, name, typ, nullable string
+		var comment sql.NullString
+		if err := rows.Scan(&schema, &table, &name, &typ, &nullable, &comment); err != nil {
+			return err
+		}
+		if t, ok := tables[schema+"."+table]; ok {
+			t.Columns = append(t.Columns, sqlColumn{
+				Name:     name,
+				Type:     typ,
+				Nullable: strings.EqualFold(nullable, "YES"),
+				Note:     nullStringPtr(comment),
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("reading columns: %w", err)
+	}
+
+	// Constraints, one row per constraint column
+	type constraint struct {
+		table *sqlTable
+		kind  string
+		fk    sqlForeignKey
+	}
+	constraints := make(map[string]*constraint)
+	var constraintOrder []string
+
+	if err := queryRows(ctx, db, queries.constraints, func(rows *sql.Rows) error {
+		var schema, table, kind, name, column, refSchema, refTable, refColumn string
+		if err := rows.Scan(&schema, &table, &kind, &name, &column, &refSchema, &refTable, &refColumn); err != nil {
+			return err
+		}
+		t, ok := tables[schema+"."+table]
+		if !ok {
+			return nil
+		}
+
+		key := schema + "." + table + "." + name
+		c, ok := constraints[key]
+		if !ok {
+			c = &constraint{table: t, kind: kind}
+			if refTable != "" {
+				c.fk.RefTable = refSchema + "." + refTable
+			}
+			constraints[key] = c
+			constraintOrder = append(constraintOrder, key)
+		}
+		c.fk.Columns = append(c.fk.Columns, column)
+		if refColumn != "" {
+			c.fk.RefColumns = append(c.fk.RefColumns, refColumn)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("reading constraints: %w", err)
+	}
+
+	for _, key := range constraintOrder {
+		c := constraints[key]
+		switch c.kind {
+		case "PRIMARY KEY":
+			c.table.PrimaryKey = c.fk.Columns
+		case "UNIQUE":
+			c.table.Unique = append(c.table.Unique, c.fk.Columns)
+		case "FOREIGN KEY":
+			c.table.ForeignKeys = append(c.table.ForeignKeys, c.fk)
+		}
+	}
+
+	return ordered, nil
+}
+
+// introspectSQLite reads tables, columns and key constraints through
+// sqlite_master and the pragma table functions.
+func introspectSQLite(ctx context.Context, db *sql.DB) ([]*sqlTable, error) {
+	var tables []*sqlTable
+
+	if err := queryRows(ctx, db, `
+SELECT name FROM sqlite_master
+WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+ORDER BY name`, func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		tables = append(tables, &sqlTable{Name: name})
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("reading tables: %w", err)
+	}
+
+	for _, table := range tables {
+		if err := introspectSQLiteTable(ctx, db, table); err != nil {
+			return nil, fmt.Errorf("reading table %s: %w", table.Name, err)
+		}
+	}
+
+	return tables, nil
+}
+
+// introspectSQLiteTable fills in the columns and keys of a SQLite table.
+func introspectSQLiteTable(ctx context.Context, db *sql.DB, table *sqlTable) error {
+	// Columns; pk holds the 1-based position within the primary key
+	pkPositions := make(map[string]int)
+	if err := queryRows(ctx, db, `
+SELECT name, type, "notnull", pk FROM pragma_table_info(?) ORDER BY cid`, func(rows *sql.Rows) error {
+		var name, typ string
+		var notNull, pk int
+		if err := rows.Scan(&name, &typ, &notNull, &pk); err != nil {
+			return err
+		}
+		table.Columns = append(table.Columns, sqlColumn{Name: name, Type: typ, Nullable: notNull == 0})
+		if pk > 0 {
+			pkPositions[name] = pk
+		}
+		return nil
+	}, table.Name); err != nil {
+		return err
+	}
+	for name := range pkPositions {
+		table.PrimaryKey = append(table.PrimaryKey, name)
+	}
+	sort.Slice(table.PrimaryKey, func(i, j int) bool {
+		return pkPositions[table.PrimaryKey[i]] < pkPositions[table.PrimaryKey[j]]
+	})
+
+	// Unique constraints, excluding the primary key's own index
+	var uniqueIndexes []string
+	if err := queryRows(ctx, db, `
+SELECT name FROM pragma_index_list(?) WHERE "unique" = 1 AND origin <> 'pk' ORDER BY name`, func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		uniqueIndexes = append(uniqueIndexes, name)
+		return nil
+	}, table.Name); err != nil {
+		return err
+	}
+	for _, index := range uniqueIndexes {
+		var cols []string
+		if err := queryRows(ctx, db, `SELECT name FROM pragma_index_info(?) ORDER BY seqno`, func(rows *sql.Rows) error {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return err
+			}
+			cols = append(cols, name)
+			return nil
+		}, index); err != nil {
+			return err
+		}
+		table.Unique = append(table.Unique, cols)
+	}
+
+	// Foreign keys, one row per column of each constraint
+	fks := make(map[int]*sqlForeignKey)
+	var fkOrder []int
+	if err := queryRows(ctx, db, `
+SELECT id, "table", "from", COALESCE("to", '') FROM pragma_foreign_key_list(?) ORDER BY id, seq`, func(rows *sql.Rows) error {
+		var id int
+		var refTable, from, to string
+		if err := rows.Scan(&id, &refTable, &from, &to); err != nil {
+			return err
+		}
+		fk, ok := fks[id]
+		if !ok {
+			fk = &sqlForeignKey{RefTable: refTable}
+			fks[id] = fk
+			fkOrder = append(fkOrder, id)
+		}
+		fk.Columns = append(fk.Columns, from)
+		if to != "" {
+			fk.RefColumns = append(fk.RefColumns, to)
+		}
+		return nil
+	}, table.Name); err != nil {
+		return err
+	}
+	for _, id := range fkOrder {
+		table.ForeignKeys = append(table.ForeignKeys, *fks[id])
+	}
+
+	return nil
+}
+
+// queryRows runs a query and calls scan for each row.
+func queryRows(ctx context.Context, db *sql.DB, query string, scan func(*sql.Rows) error, args ...any) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// nullStringPtr converts a nullable string column to an optional string.
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
@@ -0,0 +1,158 @@
+package erd
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	// Registers the pure-Go "sqlite" driver for introspection tests.
+	_ "modernc.org/sqlite"
+)
+
+const testSQLiteSchema = `
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY,
+    email TEXT NOT NULL UNIQUE,
+    bio TEXT
+);
+CREATE TABLE profiles (
+    user_id INTEGER NOT NULL PRIMARY KEY REFERENCES users (id),
+    avatar TEXT
+);
+CREATE TABLE orders (
+    id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    total REAL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE TABLE tags (id TEXT PRIMARY KEY);
+CREATE TABLE orders_tags (
+    order_id INTEGER NOT NULL REFERENCES orders (id),
+    tag_id TEXT NOT NULL REFERENCES tags (id),
+    PRIMARY KEY (order_id, tag_id)
+);
+`
+
+func openTestSQLite(t *testing.T, schema string) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.ExecContext(context.Background(), schema); err != nil {
+		t.Fatalf("creating schema: %v", err)
+	}
+	return db
+}
+
+func TestFromDatabase_SQLite(t *testing.T) {
+	db := openTestSQLite(t, testSQLiteSchema)
+
+	diagram, err := FromDatabase(context.Background(), db, DatabaseOptions{Title: "Shop", Dialect: SQLite})
+	if err != nil {
+		t.Fatalf("FromDatabase() unexpected error: %v", err)
+	}
+
+	if diagram.Title != "Shop" {
+		t.Errorf("expected title 'Shop', got %q", diagram.Title)
+	}
+	if len(diagram.Entities) != 4 {
+		t.Fatalf("expected 4 entities (join table excluded), got %d", len(diagram.Entities))
+	}
+
+	tests := []struct {
+		entity   string
+		attr     string
+		typ      string
+		key      *KeyType
+		nullable bool
+	}{
+		{"users", "id", "int64", ptr(PrimaryKey), false},
+		{"users", "email", "string", ptr(UniqueKey), false},
+		{"users", "bio", "string", nil, true},
+		{"profiles", "user_id", "int64", ptr(PrimaryKey), false},
+		{"orders", "user_id", "int64", ptr(ForeignKey), false},
+		{"orders", "total", "float64", nil, true},
+	}
+	for _, tt := range tests {
+		attr := findAttribute(diagram.Entities[tt.entity], tt.attr)
+		if attr == nil {
+			t.Errorf("%s.%s not found", tt.entity, tt.attr)
+			continue
+		}
+		if attr.Type != tt.typ {
+			t.Errorf("%s.%s type = %q, want %q", tt.entity, tt.attr, attr.Type, tt.typ)
+		}
+		if attr.Nullable != tt.nullable {
+			t.Errorf("%s.%s nullable = %v, want %v", tt.entity, tt.attr, attr.Nullable, tt.nullable)
+		}
+		if (tt.key == nil) != (attr.Key == nil) || (tt.key != nil && *tt.key != *attr.Key) {
+			t.Errorf("%s.%s key = %v, want %v", tt.entity, tt.attr, attr.Key, tt.key)
+		}
+	}
+
+	rels := map[string]Cardinality{}
+	for _, rel := range diagram.Relationships {
+		rels[rel.From+"->"+rel.To+":"+rel.Field] = rel.Cardinality
+	}
+	wantRels := map[string]Cardinality{
+		"profiles->users:user_id": OneToOne,
+		"orders->users:user_id":   ManyToOne,
+		"orders->tags:tags":       ManyToMany,
+	}
+	for key, want := range wantRels {
+		if got, ok := rels[key]; !ok || got != want {
+			t.Errorf("relationship %s = %q, want %q (all: %v)", key, got, want, rels)
+		}
+	}
+
+	if errs := diagram.Validate(); len(errs) > 0 {
+		t.Errorf("Validate() unexpected errors: %v", errs)
+	}
+}
+
+func TestFromDatabase_MatchesToSQL(t *testing.T) {
+	ddl, err := testSQLDiagram().ToSQL(SQLite)
+	if err != nil {
+		t.Fatalf("ToSQL() unexpected error: %v", err)
+	}
+	db := openTestSQLite(t, ddl)
+
+	diagram, err := FromDatabase(context.Background(), db, DatabaseOptions{Title: "Shop", Dialect: SQLite})
+	if err != nil {
+		t.Fatalf("FromDatabase() unexpected error: %v", err)
+	}
+
+	for name, want := range testSQLDiagram().Entities {
+		got := diagram.Entities[name]
+		if got == nil {
+			t.Errorf("entity %s missing from database diagram", name)
+			continue
+		}
+		for _, attr := range want.Attributes {
+			if findAttribute(got, attr.Name) == nil {
+				t.Errorf("attribute %s.%s missing from database diagram", name, attr.Name)
+			}
+		}
+	}
+}
+
+func TestFromDatabase_UnsupportedDialect(t *testing.T) {
+	if _, err := FromDatabase(context.Background(), nil, DatabaseOptions{Dialect: Dialect("oracle")}); err == nil {
+		t.Error("FromDatabase() expected error for unsupported dialect")
+	}
+}
+
+func TestFromDatabase_QueryError(t *testing.T) {
+	db := openTestSQLite(t, "CREATE TABLE a (id INTEGER);")
+
+	// SQLite has no information_schema, so the PostgreSQL queries fail
+	if _, err := FromDatabase(context.Background(), db, DatabaseOptions{Dialect: PostgreSQL}); err == nil {
+		t.Error("FromDatabase() expected error when catalog queries fail")
+	}
+}
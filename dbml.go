@@ -0,0 +1,294 @@
+package erd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ToDBML generates a DBML schema (as used by dbdiagram.io and dbdocs) from the diagram structure.
+func (d *Diagram) ToDBML() string {
+	return d.ToDBMLWith(RenderOptions{})
+}
+
+// ToDBMLWith generates a DBML schema using the given options.
+func (d *Diagram) ToDBMLWith(opts RenderOptions) string {
+	var sb strings.Builder
+
+	// Add project block if a title is present
+	if d.Title != "" && !opts.HideTitle {
+		sb.WriteString(fmt.Sprintf("Project %s {\n", quoteDBML(d.Title)))
+		if d.Description != nil {
+			sb.WriteString(fmt.Sprintf("  Note: '%s'\n", escapeDBML(*d.Description)))
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	// Write entities with their attributes (sorted by name for deterministic output)
+	ungrouped, groups := d.entityGroups(opts)
+	for _, name := range ungrouped {
+		sb.WriteString(formatDBMLTable(name, d.Entities[name], opts))
+	}
+	for _, group := range groups {
+		for _, name := range group.keys {
+			sb.WriteString(formatDBMLTable(name, d.Entities[name], opts))
+		}
+	}
+	for _, group := range groups {
+		sb.WriteString(formatDBMLTableGroup(group))
+	}
+
+	// Write relationships
+	for _, rel := range d.Relationships {
+		sb.WriteString(formatDBMLRef(d, rel))
+	}
+
+	return sb.String()
+}
+
+// formatDBMLTable formats an entity as a DBML table named by its key.
+func formatDBMLTable(name string, entity *Entity, opts RenderOptions) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Table %s {\n", quoteDBML(name)))
+
+	pk := primaryKey(entity)
+	for _, attr := range entity.Attributes {
+		sb.WriteString(formatDBMLColumn(attr, dbmlColumnKeys(entity, attr, pk), opts))
+	}
+	sb.WriteString(formatDBMLIndexes(entity, pk))
+
+	if entity.Note != nil && !opts.HideNotes {
+		sb.WriteString(fmt.Sprintf("\n  Note: '%s'\n", escapeDBML(*entity.Note)))
+	}
+
+	sb.WriteString("}\n\n")
+
+	return sb.String()
+}
+
+// formatDBMLTableGroup formats a package group as a DBML table group.
+func formatDBMLTableGroup(group packageGroup) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("TableGroup %s {\n", quoteDBML(group.name)))
+	for _, name := range group.keys {
+		sb.WriteString(fmt.Sprintf("  %s\n", quoteDBML(name)))
+	}
+	sb.WriteString("}\n\n")
+
+	return sb.String()
+}
+
+// dbmlColumnKeys returns the keys set on a column itself. Keys spanning
+// several columns are written as indexes instead.
+func dbmlColumnKeys(entity *Entity, attr *Attribute, pk []*Attribute) []KeyType {
+	var keys []KeyType
+	if len(pk) == 1 && pk[0] == attr {
+		keys = append(keys, PrimaryKey)
+	}
+	if (attr.Key != nil && *attr.Key == UniqueKey) || slices.ContainsFunc(entity.Keys, func(key *Key) bool {
+		return key.Type == UniqueKey && slices.Equal(key.Attributes, []string{attr.Name})
+	}) {
+		keys = append(keys, UniqueKey)
+	}
+	return keys
+}
+
+// formatDBMLIndexes formats composite primary and unique keys as a DBML
+// indexes block, or returns an empty string if the entity has none.
+func formatDBMLIndexes(entity *Entity, pk []*Attribute) string {
+	var indexes []string
+	if len(pk) > 1 {
+		cols := make([]string, len(pk))
+		for i, attr := range pk {
+			cols[i] = attr.Name
+		}
+		indexes = append(indexes, formatDBMLColumns(cols)+" [pk]")
+	}
+	for _, key := range entity.Keys {
+		if key.Type == UniqueKey && len(key.Attributes) > 1 {
+			indexes = append(indexes, formatDBMLColumns(key.Attributes)+" [unique]")
+		}
+	}
+	if len(indexes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n  indexes {\n")
+	for _, index := range indexes {
+		sb.WriteString("    " + index + "\n")
+	}
+	sb.WriteString("  }\n")
+	return sb.String()
+}
+
+// formatDBMLColumn formats an attribute as a DBML column with its settings.
+func formatDBMLColumn(attr *Attribute, keys []KeyType, opts RenderOptions) string {
+	var settings []string
+
+	// Add key settings; foreign keys are expressed as Ref lines instead
+	for _, kt := range keys {
+		switch kt {
+		case PrimaryKey:
+			settings = append(settings, "pk")
+		case UniqueKey:
+			settings = append(settings, "unique")
+		}
+	}
+
+	// Add nullability
+	if attr.Nullable {
+		settings = append(settings, "null")
+	} else {
+		settings = append(settings, "not null")
+	}
+
+	// Add note if present
+	if attr.Note != nil && !opts.HideNotes {
+		settings = append(settings, fmt.Sprintf("note: '%s'", escapeDBML(*attr.Note)))
+	}
+
+	return fmt.Sprintf("  %s %s [%s]\n",
+		quoteDBML(attr.Name),
+		quoteDBML(opts.typeName(attr.Type)),
+		strings.Join(settings, ", "))
+}
+
+// formatDBMLRef formats a relationship as a DBML Ref line.
+// DBML refs connect columns, so the endpoints are resolved against the
+// diagram's entities: the side holding the foreign key uses its matching
+// foreign key attribute, the referenced side uses its primary key. A
+// relationship whose columns cannot be resolved is written as a comment
+// rather than a Ref joining the wrong columns.
+func formatDBMLRef(d *Diagram, rel *Relationship) string {
+	fromCols, toCols := relationshipColumns(d, rel)
+	if len(fromCols) == 0 || len(toCols) == 0 {
+		return fmt.Sprintf("// Ref: %s %s %s (%s): joined columns not found\n",
+			quoteDBML(rel.From),
+			getDBMLCardinality(rel.Cardinality),
+			quoteDBML(rel.To),
+			strings.ReplaceAll(rel.Field, "\n", " "))
+	}
+
+	return fmt.Sprintf("Ref: %s.%s %s %s.%s\n",
+		quoteDBML(rel.From),
+		formatDBMLColumns(fromCols),
+		getDBMLCardinality(rel.Cardinality),
+		quoteDBML(rel.To),
+		formatDBMLColumns(toCols))
+}
+
+// formatDBMLColumns formats the columns of a ref or index, in parentheses
+// when there are several.
+func formatDBMLColumns(cols []string) string {
+	if len(cols) == 1 {
+		return quoteDBML(cols[0])
+	}
+	quoted := make([]string, len(cols))
+	for i, col := range cols {
+		quoted[i] = quoteDBML(col)
+	}
+	return "(" + strings.Join(quoted, ", ") + ")"
+}
+
+// relationshipColumns resolves the columns joined by a relationship: the
+// attributes it records, or else those of a foreign key declared with a
+// reference, or else a foreign key attribute named after the primary key it
+// references. Many-to-many relationships join primary keys. It returns nil
+// if either entity is missing or no foreign key is found.
+func relationshipColumns(d *Diagram, rel *Relationship) (from, to []string) {
+	fromEntity, ok := d.Entities[rel.From]
+	if !ok {
+		return nil, nil
+	}
+	toEntity, ok := d.Entities[rel.To]
+	if !ok {
+		return nil, nil
+	}
+	if len(rel.FromAttributes) > 0 && len(rel.FromAttributes) == len(rel.ToAttributes) {
+		return rel.FromAttributes, rel.ToAttributes
+	}
+
+	// The many side of a one-to-many relationship holds the reference back
+	// to the one side
+	holder, target := fromEntity, toEntity
+	if rel.Cardinality == OneToMany {
+		holder, target = toEntity, fromEntity
+	}
+	if key := declaredForeignKey(holder, target.Name); key != nil && rel.Cardinality != ManyToMany {
+		if cols := referencedColumns(target, key); len(cols) == len(key.Attributes) {
+			if rel.Cardinality == OneToMany {
+				return cols, key.Attributes
+			}
+			return key.Attributes, cols
+		}
+	}
+
+	fromRef := referencedColumn(fromEntity)
+	toRef := referencedColumn(toEntity)
+	if fromRef == nil || toRef == nil {
+		return nil, nil
+	}
+	from, to = []string{fromRef.Name}, []string{toRef.Name}
+
+	switch rel.Cardinality {
+	case ManyToMany:
+		return from, to
+	case OneToMany:
+		if fk := foreignKeyFor(toEntity, fromEntity); fk != nil {
+			return from, []string{fk.Name}
+		}
+	case OneToOne:
+		if fk := foreignKeyFor(fromEntity, toEntity); fk != nil {
+			return []string{fk.Name}, to
+		}
+		if fk := foreignKeyFor(toEntity, fromEntity); fk != nil {
+			return from, []string{fk.Name}
+		}
+	default:
+		if fk := foreignKeyFor(fromEntity, toEntity); fk != nil {
+			return []string{fk.Name}, to
+		}
+	}
+	return nil, nil
+}
+
+// getDBMLCardinality converts cardinality to a DBML relationship operator.
+func getDBMLCardinality(c Cardinality) string {
+	switch c {
+	case OneToOne:
+		return "-"
+	case OneToMany:
+		return "<"
+	case ManyToOne:
+		return ">"
+	case ManyToMany:
+		return "<>"
+	default:
+		return "-"
+	}
+}
+
+// quoteDBML quotes a DBML identifier when it contains characters other than
+// letters, digits and underscores.
+func quoteDBML(name string) string {
+	for _, r := range name {
+		if r != '_' && (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return fmt.Sprintf("\"%s\"", strings.ReplaceAll(name, "\"", "\\\""))
+		}
+	}
+	if name == "" {
+		return "\"\""
+	}
+	return name
+}
+
+// escapeDBML escapes special characters for DBML single-quoted strings.
+func escapeDBML(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "'", "\\'")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	return s
+}
@@ -0,0 +1,192 @@
+package erd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToDBML(t *testing.T) {
+	diagram := NewDiagram("Shop").
+		WithDescription("Customer's orders").
+		AddEntity(NewEntity("User").
+			WithNote("Registered customer").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("Email", "string").WithUnique()).
+			AddAttribute(NewAttribute("Bio", "string").WithNullable().WithNote("Free text"))).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("UserID", "string").WithForeignKey())).
+		AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany))
+
+	output := diagram.ToDBML()
+
+	want := []string{
+		"Project Shop {\n  Note: 'Customer\\'s orders'\n}\n",
+		"Table User {\n",
+		"  ID string [pk, not null]\n",
+		"  Email string [unique, not null]\n",
+		"  Bio string [null, note: 'Free text']\n",
+		"  Note: 'Registered customer'\n",
+		"  UserID string [not null]\n",
+		"Ref: User.ID < Order.UserID\n",
+	}
+	for _, w := range want {
+		if !strings.Contains(output, w) {
+			t.Errorf("ToDBML() should contain %q, got:\n%s", w, output)
+		}
+	}
+
+	if strings.Index(output, "Table Order") > strings.Index(output, "Table User") {
+		t.Errorf("ToDBML() should order tables by name, got:\n%s", output)
+	}
+}
+
+func TestToDBML_WithEmptyTitle(t *testing.T) {
+	diagram := &Diagram{
+		Title:    "",
+		Entities: make(map[string]*Entity),
+	}
+	diagram.AddEntity(NewEntity("User").AddAttribute(NewAttribute("ID", "string")))
+
+	output := diagram.ToDBML()
+
+	if strings.Contains(output, "Project") {
+		t.Errorf("ToDBML() should not include project for empty title, got:\n%s", output)
+	}
+}
+
+func TestFormatDBMLRef(t *testing.T) {
+	diagram := NewDiagram("Test").
+		AddEntity(NewEntity("User").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("ProfileID", "string").WithForeignKey())).
+		AddEntity(NewEntity("Profile").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Group").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()))
+
+	tests := []struct {
+		name string
+		rel  *Relationship
+		want string
+	}{
+		{"one to one uses foreign key", NewRelationship("User", "Profile", "Profile", OneToOne), "Ref: User.ProfileID - Profile.ID\n"},
+		{"many to one uses foreign key", NewRelationship("User", "Profile", "Profile", ManyToOne), "Ref: User.ProfileID > Profile.ID\n"},
+		{"many to many uses primary keys", NewRelationship("User", "Group", "Groups", ManyToMany), "Ref: User.ID <> Group.ID\n"},
+		{"missing entity", NewRelationship("User", "Missing", "Missing", OneToOne), "// Ref: User - Missing (Missing): joined columns not found\n"},
+		{"one to one held by the other side", NewRelationship("Profile", "User", "Owner", OneToOne), "Ref: Profile.ID - User.ProfileID\n"},
+		{"foreign key to another entity", NewRelationship("Group", "User", "Members", OneToMany), "// Ref: Group < User (Members): joined columns not found\n"},
+		{
+			"recorded attributes",
+			NewRelationship("Group", "User", "Owner", ManyToOne).WithAttributes([]string{"ID"}, []string{"ProfileID"}),
+			"Ref: Group.ID > User.ProfileID\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDBMLRef(diagram, tt.rel); got != tt.want {
+				t.Errorf("formatDBMLRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToDBML_UnrelatedForeignKey(t *testing.T) {
+	diagram := NewDiagram("Shop").
+		AddEntity(NewEntity("User").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Shop").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("ShopID", "string").WithForeignKey())).
+		AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany))
+
+	output := diagram.ToDBML()
+	if strings.Contains(output, "Order.ShopID") {
+		t.Errorf("ToDBML() should not join User to the Shop foreign key, got:\n%s", output)
+	}
+	if !strings.Contains(output, "// Ref: User < Order (Orders): joined columns not found\n") {
+		t.Errorf("ToDBML() should report the unresolved ref, got:\n%s", output)
+	}
+}
+
+func TestGetDBMLCardinality(t *testing.T) {
+	tests := []struct {
+		name        string
+		cardinality Cardinality
+		want        string
+	}{
+		{"one to one", OneToOne, "-"},
+		{"one to many", OneToMany, "<"},
+		{"many to one", ManyToOne, ">"},
+		{"many to many", ManyToMany, "<>"},
+		{"default/unknown", Cardinality("unknown"), "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getDBMLCardinality(tt.cardinality); got != tt.want {
+				t.Errorf("getDBMLCardinality() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteDBML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"identifier", "User_1", "User_1"},
+		{"with space", "Order Line", `"Order Line"`},
+		{"with dot", "pkg.User", `"pkg.User"`},
+		{"empty", "", `""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quoteDBML(tt.input); got != tt.want {
+				t.Errorf("quoteDBML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeDBML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"backslash", "test\\value", "test\\\\value"},
+		{"single quote", "it's", "it\\'s"},
+		{"newline", "test\nvalue", "test\\nvalue"},
+		{"no special chars", "test value", "test value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeDBML(tt.input); got != tt.want {
+				t.Errorf("escapeDBML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToDBML_CompositeKeys(t *testing.T) {
+	output := testCompositeDiagram().ToDBML()
+
+	for _, exp := range []string{
+		"Table LineItem {\n  TenantID string [not null]\n  OrderID string [not null]\n  SKU string [not null]\n\n  indexes {\n    (TenantID, OrderID, SKU) [pk]\n  }\n}\n",
+		"  indexes {\n    (TenantID, ID) [pk]\n    (TenantID, Number) [unique]\n  }\n",
+		"Table Tenant {\n  ID string [pk, not null]\n}\n",
+		"Ref: Order.(TenantID, ID) < LineItem.(TenantID, OrderID)\n",
+	} {
+		if !strings.Contains(output, exp) {
+			t.Errorf("ToDBML() should contain %q, got:\n%s", exp, output)
+		}
+	}
+}
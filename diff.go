@@ -0,0 +1,466 @@
+package erd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ChangeKind identifies the kind of structural change between two diagrams.
+type ChangeKind string
+
+// Change kind constants.
+const (
+	EntityAdded                      ChangeKind = "entity-added"
+	EntityRemoved                    ChangeKind = "entity-removed"
+	EntityRenamed                    ChangeKind = "entity-renamed"
+	AttributeAdded                   ChangeKind = "attribute-added"
+	AttributeRemoved                 ChangeKind = "attribute-removed"
+	AttributeTypeChanged             ChangeKind = "attribute-type-changed"
+	AttributeNullabilityChanged      ChangeKind = "attribute-nullability-changed"
+	AttributeKeyChanged              ChangeKind = "attribute-key-changed"
+	KeyAdded                         ChangeKind = "key-added"
+	KeyRemoved                       ChangeKind = "key-removed"
+	KeyChanged                       ChangeKind = "key-changed"
+	RelationshipAdded                ChangeKind = "relationship-added"
+	RelationshipRemoved              ChangeKind = "relationship-removed"
+	RelationshipCardinalityChanged   ChangeKind = "relationship-cardinality-changed"
+	RelationshipParticipationChanged ChangeKind = "relationship-participation-changed"
+	RelationshipAttributesChanged    ChangeKind = "relationship-attributes-changed"
+)
+
+// Change describes a single structural difference between two diagrams.
+//
+// Entity names the affected entity (its new name for renames, the source
+// entity for relationships). Attribute is set for attribute changes, Key for
+// key group changes; To and Field identify relationships. Old and New hold
+// the values before and after the change: names for renames, types,
+// "nullable"/"not null", key types, key groups such as
+// "FK (TenantID, OrderID) → Order (TenantID, ID)", cardinalities, the
+// participation at the From and To end such as "1..1, 0..N" or the joined
+// attributes such as "UserID → ID". A key or joined attributes that are not
+// set are represented by an empty string.
+type Change struct {
+	Kind      ChangeKind `json:"kind"`
+	Entity    string     `json:"entity"`
+	Attribute string     `json:"attribute,omitempty"`
+	Key       string     `json:"key,omitempty"`
+	To        string     `json:"to,omitempty"`
+	Field     string     `json:"field,omitempty"`
+	Old       string     `json:"old,omitempty"`
+	New       string     `json:"new,omitempty"`
+}
+
+// ChangeSet lists the structural changes between two diagrams.
+type ChangeSet struct {
+	Changes []Change `json:"changes"`
+}
+
+// Diff compares two diagrams and returns the structural changes needed to
+// turn before into after.
+//
+// Entities are matched by name. A removed entity whose attributes match
+// exactly one added entity is reported as a rename, and relationships are
+// compared after applying renames. Attributes and key groups are matched by
+// name and relationships by source, target and field, in order when several
+// share them.
+func Diff(before, after *Diagram) *ChangeSet {
+	cs := &ChangeSet{Changes: []Change{}}
+
+	renames := detectRenames(before, after)
+	renamedFrom := make(map[string]string, len(renames))
+	for oldName, newName := range renames {
+		renamedFrom[newName] = oldName
+		cs.Changes = append(cs.Changes, Change{
+			Kind:   EntityRenamed,
+			Entity: newName,
+			Old:    oldName,
+			New:    newName,
+		})
+	}
+
+	for _, name := range before.entityNames() {
+		if _, ok := after.Entities[name]; !ok && renames[name] == "" {
+			cs.Changes = append(cs.Changes, Change{Kind: EntityRemoved, Entity: name})
+		}
+	}
+
+	for _, name := range after.entityNames() {
+		if _, ok := before.Entities[name]; !ok && renamedFrom[name] == "" {
+			cs.Changes = append(cs.Changes, Change{Kind: EntityAdded, Entity: name})
+		}
+	}
+
+	// Entity-level changes are listed by entity name
+	sort.SliceStable(cs.Changes, func(i, j int) bool {
+		return cs.Changes[i].Entity < cs.Changes[j].Entity
+	})
+
+	for _, name := range after.entityNames() {
+		oldName := name
+		if from, ok := renamedFrom[name]; ok {
+			oldName = from
+		}
+		if oldEntity, ok := before.Entities[oldName]; ok {
+			cs.Changes = append(cs.Changes, diffAttributes(name, oldEntity, after.Entities[name])...)
+			cs.Changes = append(cs.Changes, diffKeys(name, oldEntity, after.Entities[name], renames)...)
+		}
+	}
+
+	cs.Changes = append(cs.Changes, diffRelationships(before.Relationships, after.Relationships, renames)...)
+
+	return cs
+}
+
+// Empty reports whether the change set has no changes.
+func (c *ChangeSet) Empty() bool {
+	return len(c.Changes) == 0
+}
+
+// String renders the change set as human-readable text, one change per line.
+func (c *ChangeSet) String() string {
+	if c.Empty() {
+		return "No changes\n"
+	}
+
+	var sb strings.Builder
+	for _, change := range c.Changes {
+		sb.WriteString(change.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// WriteJSON writes the change set as an indented JSON document.
+func (c *ChangeSet) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return fmt.Errorf("encoding change set: %w", err)
+	}
+	return nil
+}
+
+// String describes the change in a single sentence.
+func (c Change) String() string {
+	attr := c.Entity + "." + c.Attribute
+	rel := c.Entity + "→" + c.To
+
+	switch c.Kind {
+	case EntityAdded:
+		return fmt.Sprintf("Added entity %s", c.Entity)
+	case EntityRemoved:
+		return fmt.Sprintf("Removed entity %s", c.Entity)
+	case EntityRenamed:
+		return fmt.Sprintf("Renamed entity %s to %s", c.Old, c.New)
+	case AttributeAdded:
+		return fmt.Sprintf("Added %s (%s)", attr, c.New)
+	case AttributeRemoved:
+		return fmt.Sprintf("Removed %s (%s)", attr, c.Old)
+	case AttributeTypeChanged:
+		return fmt.Sprintf("%s changed type from %s to %s", attr, c.Old, c.New)
+	case AttributeNullabilityChanged:
+		return fmt.Sprintf("%s became %s", attr, c.New)
+	case AttributeKeyChanged:
+		switch {
+		case c.Old == "":
+			return fmt.Sprintf("%s became %s", attr, c.New)
+		case c.New == "":
+			return fmt.Sprintf("%s is no longer %s", attr, c.Old)
+		default:
+			return fmt.Sprintf("%s changed from %s to %s", attr, c.Old, c.New)
+		}
+	case KeyAdded:
+		return fmt.Sprintf("Added key %s.%s (%s)", c.Entity, c.Key, c.New)
+	case KeyRemoved:
+		return fmt.Sprintf("Removed key %s.%s (%s)", c.Entity, c.Key, c.Old)
+	case KeyChanged:
+		return fmt.Sprintf("Key %s.%s changed from %s to %s", c.Entity, c.Key, c.Old, c.New)
+	case RelationshipAdded:
+		return fmt.Sprintf("Added relationship %s (%s, %s)", rel, c.Field, c.New)
+	case RelationshipRemoved:
+		return fmt.Sprintf("Removed relationship %s (%s, %s)", rel, c.Field, c.Old)
+	case RelationshipCardinalityChanged:
+		return fmt.Sprintf("%s changed to %s", rel, c.New)
+	case RelationshipParticipationChanged:
+		return fmt.Sprintf("%s participation changed from %s to %s", rel, c.Old, c.New)
+	case RelationshipAttributesChanged:
+		return fmt.Sprintf("%s joined attributes changed from %s to %s", rel, conflictValue(c.Old), conflictValue(c.New))
+	default:
+		return fmt.Sprintf("%s %s", c.Kind, c.Entity)
+	}
+}
+
+// detectRenames pairs removed and added entities with identical attributes.
+// Only unambiguous one-to-one matches are treated as renames.
+func detectRenames(before, after *Diagram) map[string]string {
+	var removed, added []string
+	for _, name := range before.entityNames() {
+		if _, ok := after.Entities[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	for _, name := range after.entityNames() {
+		if _, ok := before.Entities[name]; !ok {
+			added = append(added, name)
+		}
+	}
+
+	candidates := func(entity *Entity, names []string, d *Diagram) []string {
+		var matches []string
+		sig := attributeSignature(entity)
+		for _, name := range names {
+			if sig != "" && attributeSignature(d.Entities[name]) == sig {
+				matches = append(matches, name)
+			}
+		}
+		return matches
+	}
+
+	renames := make(map[string]string)
+	for _, oldName := range removed {
+		matches := candidates(before.Entities[oldName], added, after)
+		if len(matches) != 1 {
+			continue
+		}
+		if back := candidates(after.Entities[matches[0]], removed, before); len(back) == 1 {
+			renames[oldName] = matches[0]
+		}
+	}
+	return renames
+}
+
+// attributeSignature summarizes an entity's attribute names and types.
+func attributeSignature(entity *Entity) string {
+	parts := make([]string, 0, len(entity.Attributes))
+	for _, attr := range entity.Attributes {
+		parts = append(parts, attr.Name+" "+attr.Type)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// diffAttributes compares the attributes of an entity present in both diagrams.
+func diffAttributes(name string, oldEntity, newEntity *Entity) []Change {
+	var changes []Change
+
+	oldAttrs := make(map[string]*Attribute, len(oldEntity.Attributes))
+	for _, attr := range oldEntity.Attributes {
+		oldAttrs[attr.Name] = attr
+	}
+	newAttrs := make(map[string]*Attribute, len(newEntity.Attributes))
+	for _, attr := range newEntity.Attributes {
+		newAttrs[attr.Name] = attr
+	}
+
+	for _, attr := range oldEntity.Attributes {
+		if _, ok := newAttrs[attr.Name]; !ok {
+			changes = append(changes, Change{Kind: AttributeRemoved, Entity: name, Attribute: attr.Name, Old: attr.Type})
+		}
+	}
+
+	for _, attr := range newEntity.Attributes {
+		prev, ok := oldAttrs[attr.Name]
+		if !ok {
+			changes = append(changes, Change{Kind: AttributeAdded, Entity: name, Attribute: attr.Name, New: attr.Type})
+			continue
+		}
+
+		if prev.Type != attr.Type {
+			changes = append(changes, Change{
+				Kind: AttributeTypeChanged, Entity: name, Attribute: attr.Name,
+				Old: prev.Type, New: attr.Type,
+			})
+		}
+		if prev.Nullable != attr.Nullable {
+			changes = append(changes, Change{
+				Kind: AttributeNullabilityChanged, Entity: name, Attribute: attr.Name,
+				Old: nullability(prev.Nullable), New: nullability(attr.Nullable),
+			})
+		}
+		if oldKey, newKey := keyString(prev.Key), keyString(attr.Key); oldKey != newKey {
+			changes = append(changes, Change{
+				Kind: AttributeKeyChanged, Entity: name, Attribute: attr.Name,
+				Old: oldKey, New: newKey,
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffKeys compares the key groups of an entity present in both diagrams.
+// References to renamed entities are compared by their new names.
+func diffKeys(name string, oldEntity, newEntity *Entity, renames map[string]string) []Change {
+	var changes []Change
+
+	oldKeys := make(map[string]string, len(oldEntity.Keys))
+	for _, key := range oldEntity.Keys {
+		oldKeys[key.Name] = keyDescription(key, renames)
+	}
+	newKeys := make(map[string]string, len(newEntity.Keys))
+	for _, key := range newEntity.Keys {
+		newKeys[key.Name] = keyDescription(key, nil)
+	}
+
+	for _, key := range oldEntity.Keys {
+		if _, ok := newKeys[key.Name]; !ok {
+			changes = append(changes, Change{Kind: KeyRemoved, Entity: name, Key: key.Name, Old: oldKeys[key.Name]})
+		}
+	}
+
+	for _, key := range newEntity.Keys {
+		prev, ok := oldKeys[key.Name]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Kind: KeyAdded, Entity: name, Key: key.Name, New: newKeys[key.Name]})
+		case prev != newKeys[key.Name]:
+			changes = append(changes, Change{
+				Kind: KeyChanged, Entity: name, Key: key.Name,
+				Old: prev, New: newKeys[key.Name],
+			})
+		}
+	}
+
+	return changes
+}
+
+// keyDescription describes a key group by its type, attributes and
+// reference, such as "FK (TenantID, OrderID) → Order (TenantID, ID)".
+// Referenced entities found in renames are described by their new name.
+func keyDescription(key *Key, renames map[string]string) string {
+	desc := fmt.Sprintf("%s (%s)", key.Type, strings.Join(key.Attributes, ", "))
+	if key.Reference == nil {
+		return desc
+	}
+
+	entity := key.Reference.Entity
+	if to, ok := renames[entity]; ok {
+		entity = to
+	}
+	desc += " → " + entity
+	if len(key.Reference.Attributes) > 0 {
+		desc += fmt.Sprintf(" (%s)", strings.Join(key.Reference.Attributes, ", "))
+	}
+	return desc
+}
+
+// keyDescriptionType returns the key type a [keyDescription] starts with,
+// or "" for an empty description.
+func keyDescriptionType(desc string) string {
+	kt, _, _ := strings.Cut(desc, " ")
+	return kt
+}
+
+// diffRelationships compares relationships by source, target and field,
+// after mapping renamed entities in the old diagram to their new names.
+func diffRelationships(oldRels, newRels []*Relationship, renames map[string]string) []Change {
+	var changes []Change
+
+	rename := func(name string) string {
+		if to, ok := renames[name]; ok {
+			return to
+		}
+		return name
+	}
+	key := func(from, to, field string) string {
+		return from + "\x00" + to + "\x00" + field
+	}
+
+	// Relationships sharing from, to and field are paired in order, so
+	// duplicates are compared one to one rather than overwriting each other
+	oldByKey := make(map[string][]*Relationship, len(oldRels))
+	for _, rel := range oldRels {
+		k := key(rename(rel.From), rename(rel.To), rel.Field)
+		oldByKey[k] = append(oldByKey[k], rel)
+	}
+	prevOf := make(map[*Relationship]*Relationship, len(newRels))
+	paired := make(map[*Relationship]bool, len(oldRels))
+	for _, rel := range newRels {
+		k := key(rel.From, rel.To, rel.Field)
+		if prevs := oldByKey[k]; len(prevs) > 0 {
+			prevOf[rel] = prevs[0]
+			paired[prevs[0]] = true
+			oldByKey[k] = prevs[1:]
+		}
+	}
+
+	for _, rel := range oldRels {
+		if !paired[rel] {
+			changes = append(changes, Change{
+				Kind: RelationshipRemoved, Entity: rename(rel.From), To: rename(rel.To), Field: rel.Field,
+				Old: string(rel.Cardinality),
+			})
+		}
+	}
+
+	for _, rel := range newRels {
+		prev, ok := prevOf[rel]
+		switch {
+		case !ok:
+			changes = append(changes, Change{
+				Kind: RelationshipAdded, Entity: rel.From, To: rel.To, Field: rel.Field,
+				New: string(rel.Cardinality),
+			})
+		case prev.Cardinality != rel.Cardinality:
+			changes = append(changes, Change{
+				Kind: RelationshipCardinalityChanged, Entity: rel.From, To: rel.To, Field: rel.Field,
+				Old: string(prev.Cardinality), New: string(rel.Cardinality),
+			})
+		}
+		// Participation implied by the cardinality changes with it, so only
+		// participation set on either version is compared
+		if ok && (prev.hasParticipation() || rel.hasParticipation()) {
+			if old, next := participationString(prev), participationString(rel); old != next {
+				changes = append(changes, Change{
+					Kind: RelationshipParticipationChanged, Entity: rel.From, To: rel.To, Field: rel.Field,
+					Old: old, New: next,
+				})
+			}
+		}
+		if ok {
+			if old, next := joinedString(prev), joinedString(rel); old != next {
+				changes = append(changes, Change{
+					Kind: RelationshipAttributesChanged, Entity: rel.From, To: rel.To, Field: rel.Field,
+					Old: old, New: next,
+				})
+			}
+		}
+	}
+
+	return changes
+}
+
+// participationString describes the participation at each end of a
+// relationship, such as "1..1, 0..N".
+func participationString(rel *Relationship) string {
+	from, to := rel.Participation()
+	return string(from) + ", " + string(to)
+}
+
+// joinedString describes the attributes a relationship joins, such as
+// "TenantID, OrderID → TenantID, ID", or "" when they are not recorded.
+func joinedString(rel *Relationship) string {
+	if len(rel.FromAttributes) == 0 && len(rel.ToAttributes) == 0 {
+		return ""
+	}
+	return strings.Join(rel.FromAttributes, ", ") + " → " + strings.Join(rel.ToAttributes, ", ")
+}
+
+// nullability describes whether an attribute is nullable.
+func nullability(nullable bool) string {
+	if nullable {
+		return "nullable"
+	}
+	return "not null"
+}
+
+// keyString returns the key type as a string, or "" if unset.
+func keyString(key *KeyType) string {
+	if key == nil {
+		return ""
+	}
+	return string(*key)
+}
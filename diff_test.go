@@ -0,0 +1,357 @@
+package erd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testDiffBase() *Diagram {
+	return NewDiagram("Shop").
+		AddEntity(NewEntity("User").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("Email", "string")).
+			AddAttribute(NewAttribute("Age", "int"))).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("UserID", "string").WithForeignKey())).
+		AddRelationship(NewRelationship("Order", "User", "User", OneToOne))
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(d *Diagram)
+		want   []string
+	}{
+		{
+			name:   "no changes",
+			modify: func(*Diagram) {},
+		},
+		{
+			name: "entity added",
+			modify: func(d *Diagram) {
+				d.AddEntity(NewEntity("Shop").AddAttribute(NewAttribute("ID", "string")))
+			},
+			want: []string{"Added entity Shop"},
+		},
+		{
+			name: "entity removed",
+			modify: func(d *Diagram) {
+				delete(d.Entities, "Order")
+				d.Relationships = nil
+			},
+			want: []string{
+				"Removed entity Order",
+				"Removed relationship Order→User (User, one-to-one)",
+			},
+		},
+		{
+			name: "entity renamed",
+			modify: func(d *Diagram) {
+				d.Entities["Customer"] = d.Entities["User"]
+				d.Entities["Customer"].Name = "Customer"
+				delete(d.Entities, "User")
+				d.Relationships[0].To = "Customer"
+			},
+			want: []string{"Renamed entity User to Customer"},
+		},
+		{
+			name: "attributes added and removed",
+			modify: func(d *Diagram) {
+				user := d.Entities["User"]
+				user.Attributes = append(user.Attributes[:2], NewAttribute("Phone", "string"))
+			},
+			want: []string{
+				"Removed User.Age (int)",
+				"Added User.Phone (string)",
+			},
+		},
+		{
+			name: "attribute type changed",
+			modify: func(d *Diagram) {
+				d.Entities["User"].Attributes[2].Type = "int64"
+			},
+			want: []string{"User.Age changed type from int to int64"},
+		},
+		{
+			name: "attribute became nullable",
+			modify: func(d *Diagram) {
+				d.Entities["User"].Attributes[1].WithNullable()
+			},
+			want: []string{"User.Email became nullable"},
+		},
+		{
+			name: "attribute key changes",
+			modify: func(d *Diagram) {
+				d.Entities["User"].Attributes[1].WithUnique()
+				d.Entities["Order"].Attributes[1].Key = nil
+				d.Entities["Order"].Attributes[0].WithUnique()
+			},
+			want: []string{
+				"Order.ID changed from PK to UK",
+				"Order.UserID is no longer FK",
+				"User.Email became UK",
+			},
+		},
+		{
+			name: "cardinality changed",
+			modify: func(d *Diagram) {
+				d.Relationships[0].Cardinality = ManyToOne
+			},
+			want: []string{"Order→User changed to many-to-one"},
+		},
+		{
+			name: "participation changed",
+			modify: func(d *Diagram) {
+				d.Relationships[0].WithToParticipation(ZeroOrOne)
+			},
+			want: []string{"Order→User participation changed from 1..1, 1..1 to 1..1, 0..1"},
+		},
+		{
+			name: "joined attributes recorded",
+			modify: func(d *Diagram) {
+				d.Relationships[0].WithAttributes([]string{"UserID"}, []string{"ID"})
+			},
+			want: []string{"Order→User joined attributes changed from none to UserID → ID"},
+		},
+		{
+			name: "default participation follows cardinality",
+			modify: func(d *Diagram) {
+				d.Relationships[0].Cardinality = OneToMany
+			},
+			want: []string{"Order→User changed to one-to-many"},
+		},
+		{
+			name: "relationship added",
+			modify: func(d *Diagram) {
+				d.AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany))
+			},
+			want: []string{"Added relationship User→Order (Orders, one-to-many)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := testDiffBase()
+			tt.modify(after)
+
+			cs := Diff(testDiffBase(), after)
+
+			got := make([]string, len(cs.Changes))
+			for i, c := range cs.Changes {
+				got[i] = c.String()
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Diff() changes:\ngot  %q\nwant %q", got, tt.want)
+			}
+			if cs.Empty() != (len(tt.want) == 0) {
+				t.Errorf("Empty() = %v, want %v", cs.Empty(), len(tt.want) == 0)
+			}
+		})
+	}
+}
+
+func TestDiff_DuplicateRelationships(t *testing.T) {
+	// Relationships sharing from, to and field are paired in order
+	withDuplicate := func(cardinality Cardinality) *Diagram {
+		return testDiffBase().AddRelationship(NewRelationship("Order", "User", "User", cardinality))
+	}
+
+	tests := []struct {
+		name          string
+		before, after *Diagram
+		want          []string
+	}{
+		{
+			name:   "duplicate added",
+			before: testDiffBase(),
+			after:  withDuplicate(ManyToOne),
+			want:   []string{"Added relationship Order→User (User, many-to-one)"},
+		},
+		{
+			name:   "duplicate removed",
+			before: withDuplicate(ManyToOne),
+			after:  testDiffBase(),
+			want:   []string{"Removed relationship Order→User (User, many-to-one)"},
+		},
+		{
+			name:   "duplicate changed",
+			before: withDuplicate(ManyToOne),
+			after:  withDuplicate(OneToMany),
+			want:   []string{"Order→User changed to one-to-many"},
+		},
+		{
+			name:   "duplicates unchanged",
+			before: withDuplicate(ManyToOne),
+			after:  withDuplicate(ManyToOne),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := Diff(tt.before, tt.after)
+
+			got := make([]string, len(cs.Changes))
+			for i, c := range cs.Changes {
+				got[i] = c.String()
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Diff() changes:\ngot  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff_Renames(t *testing.T) {
+	t.Run("ambiguous match is not a rename", func(t *testing.T) {
+		after := testDiffBase()
+		attrs := after.Entities["User"].Attributes
+		delete(after.Entities, "User")
+		after.AddEntity(&Entity{Name: "Customer", Attributes: attrs})
+		after.AddEntity(&Entity{Name: "Member", Attributes: attrs})
+		after.Relationships = nil
+
+		cs := Diff(testDiffBase(), after)
+		for _, c := range cs.Changes {
+			if c.Kind == EntityRenamed {
+				t.Errorf("unexpected rename: %s", c)
+			}
+		}
+	})
+
+	t.Run("renamed entity attributes are compared", func(t *testing.T) {
+		after := testDiffBase()
+		user := after.Entities["User"]
+		delete(after.Entities, "User")
+		user.Name = "Customer"
+		user.Attributes[1].WithNullable()
+		after.AddEntity(user)
+		after.Relationships[0].To = "Customer"
+
+		cs := Diff(testDiffBase(), after)
+		got := cs.String()
+		want := "Renamed entity User to Customer\nCustomer.Email became nullable\n"
+		if got != want {
+			t.Errorf("Diff() =\n%s\nwant\n%s", got, want)
+		}
+	})
+}
+
+func TestDiff_Keys(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(d *Diagram)
+		want   []string
+	}{
+		{
+			name: "key added",
+			modify: func(d *Diagram) {
+				d.Entities["LineItem"].AddKey(NewKey("uk_sku", UniqueKey, "OrderID", "SKU"))
+			},
+			want: []string{"Added key LineItem.uk_sku (UK (OrderID, SKU))"},
+		},
+		{
+			name: "key removed",
+			modify: func(d *Diagram) {
+				d.Entities["LineItem"].Keys = d.Entities["LineItem"].Keys[:1]
+			},
+			want: []string{"Removed key LineItem.fk_order (FK (TenantID, OrderID) → Order (TenantID, ID))"},
+		},
+		{
+			name: "key attributes changed",
+			modify: func(d *Diagram) {
+				d.Entities["LineItem"].Keys[0].Attributes = []string{"OrderID", "SKU"}
+			},
+			want: []string{"Key LineItem.pk changed from PK (TenantID, OrderID, SKU) to PK (OrderID, SKU)"},
+		},
+		{
+			name: "key reference changed",
+			modify: func(d *Diagram) {
+				d.Entities["Order"].Keys[0].Reference.Attributes = nil
+			},
+			want: []string{"Key Order.fk_tenant changed from FK (TenantID) → Tenant (ID) to FK (TenantID) → Tenant"},
+		},
+		{
+			name: "reference to renamed entity",
+			modify: func(d *Diagram) {
+				tenant := d.Entities["Tenant"]
+				delete(d.Entities, "Tenant")
+				tenant.Name = "Account"
+				d.AddEntity(tenant)
+				d.Entities["Order"].Keys[0].Reference.Entity = "Account"
+			},
+			want: []string{"Renamed entity Tenant to Account"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := testCompositeDiagram()
+			tt.modify(after)
+
+			cs := Diff(testCompositeDiagram(), after)
+
+			got := make([]string, len(cs.Changes))
+			for i, c := range cs.Changes {
+				got[i] = c.String()
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Diff() changes:\ngot  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangeSet_String(t *testing.T) {
+	if got := Diff(testDiffBase(), testDiffBase()).String(); got != "No changes\n" {
+		t.Errorf("String() = %q, want %q", got, "No changes\n")
+	}
+}
+
+func TestChangeSet_WriteJSON(t *testing.T) {
+	after := testDiffBase()
+	after.Entities["User"].Attributes[1].WithNullable()
+	after.Relationships[0].Cardinality = ManyToOne
+
+	var buf bytes.Buffer
+	if err := Diff(testDiffBase(), after).WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() unexpected error: %v", err)
+	}
+
+	var decoded ChangeSet
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteJSON() produced invalid JSON: %v", err)
+	}
+
+	want := []Change{
+		{Kind: AttributeNullabilityChanged, Entity: "User", Attribute: "Email", Old: "not null", New: "nullable"},
+		{Kind: RelationshipCardinalityChanged, Entity: "Order", To: "User", Field: "User", Old: "one-to-one", New: "many-to-one"},
+	}
+	if len(decoded.Changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %s", len(want), len(decoded.Changes), buf.String())
+	}
+	for i := range want {
+		if decoded.Changes[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, decoded.Changes[i], want[i])
+		}
+	}
+
+	if !strings.Contains(buf.String(), `"kind": "attribute-nullability-changed"`) {
+		t.Errorf("expected kind in JSON output, got:\n%s", buf.String())
+	}
+
+	// An empty change set encodes an empty list rather than null
+	buf.Reset()
+	if err := Diff(testDiffBase(), testDiffBase()).WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"changes": []`) {
+		t.Errorf("expected empty changes list, got %s", buf.String())
+	}
+
+	if err := Diff(testDiffBase(), after).WriteJSON(failingWriter{}); err == nil {
+		t.Error("WriteJSON() expected error from writer")
+	}
+}
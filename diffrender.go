@@ -0,0 +1,359 @@
+package erd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// diffStatus marks how an element changed between two diagrams.
+type diffStatus string
+
+// Diff status constants; unchanged elements have an empty status.
+const (
+	statusAdded    diffStatus = "added"
+	statusRemoved  diffStatus = "removed"
+	statusModified diffStatus = "modified"
+)
+
+// diffColor holds the outline and fill colors for a diff status.
+type diffColor struct {
+	stroke string
+	fill   string
+}
+
+// diffColors maps each status to green, red or amber.
+var diffColors = map[diffStatus]diffColor{
+	statusAdded:    {stroke: "#2e7d32", fill: "#c8e6c9"},
+	statusRemoved:  {stroke: "#c62828", fill: "#ffcdd2"},
+	statusModified: {stroke: "#ff8f00", fill: "#ffe0b2"},
+}
+
+// diffMarkers prefixes attributes in formats that cannot color them.
+var diffMarkers = map[diffStatus]string{
+	statusAdded:    "+ ",
+	statusRemoved:  "- ",
+	statusModified: "~ ",
+}
+
+// diffView is the union of two diagrams with the status of every element.
+type diffView struct {
+	title         string
+	entities      []*diffEntity
+	relationships []*diffRelationship
+}
+
+// diffEntity is an entity in a diff view, keyed as in its diagram.
+type diffEntity struct {
+	entity     *Entity
+	name       string
+	status     diffStatus
+	attributes []*diffAttribute
+}
+
+// diffAttribute is an attribute in a diff view.
+type diffAttribute struct {
+	attr   *Attribute
+	status diffStatus
+}
+
+// diffRelationship is a relationship in a diff view.
+type diffRelationship struct {
+	rel    *Relationship
+	status diffStatus
+}
+
+// RenderDiff renders a single diagram highlighting the changes from before
+// to after. Added elements are green, removed elements red and modified
+// elements amber. Removed entities, attributes and relationships are kept
+// in the output so reviewers can see what went away.
+//
+// Mermaid colors entities with classDef statements and annotates attributes
+// and relationship labels, since Mermaid cannot style them individually.
+// DOT colors entities and edges and prefixes changed attributes with
+// "+", "-" or "~". Other formats return an error.
+func RenderDiff(before, after *Diagram, format Format) (string, error) {
+	view := newDiffView(before, after)
+
+	switch format {
+	case MermaidFormat:
+		return view.toMermaid(), nil
+	case DOTFormat:
+		return view.toDOT(), nil
+	default:
+		return "", fmt.Errorf("diff rendering is not supported for format %q", format)
+	}
+}
+
+// newDiffView merges two diagrams using the changes reported by [Diff].
+func newDiffView(before, after *Diagram) *diffView {
+	cs := Diff(before, after)
+
+	entityStatus := make(map[string]diffStatus)
+	attrStatus := make(map[string]diffStatus)
+	relStatus := make(map[string]diffStatus)
+	renamedFrom := make(map[string]string)
+	removedAttrs := make(map[string][]string)
+	var removedEntities []string
+	var removedRels []Change
+
+	relKey := func(from, to, field string) string {
+		return from + "\x00" + to + "\x00" + field
+	}
+
+	for _, c := range cs.Changes {
+		switch c.Kind {
+		case EntityAdded:
+			entityStatus[c.Entity] = statusAdded
+		case EntityRemoved:
+			removedEntities = append(removedEntities, c.Entity)
+		case EntityRenamed:
+			entityStatus[c.Entity] = statusModified
+			renamedFrom[c.Entity] = c.Old
+		case AttributeAdded:
+			entityStatus[c.Entity] = statusModified
+			attrStatus[c.Entity+"."+c.Attribute] = statusAdded
+		case AttributeRemoved:
+			entityStatus[c.Entity] = statusModified
+			removedAttrs[c.Entity] = append(removedAttrs[c.Entity], c.Attribute)
+		case AttributeTypeChanged, AttributeNullabilityChanged, AttributeKeyChanged:
+			entityStatus[c.Entity] = statusModified
+			attrStatus[c.Entity+"."+c.Attribute] = statusModified
+		case KeyAdded, KeyRemoved, KeyChanged:
+			entityStatus[c.Entity] = statusModified
+		case RelationshipAdded:
+			relStatus[relKey(c.Entity, c.To, c.Field)] = statusAdded
+		case RelationshipCardinalityChanged, RelationshipParticipationChanged, RelationshipAttributesChanged:
+			relStatus[relKey(c.Entity, c.To, c.Field)] = statusModified
+		case RelationshipRemoved:
+			removedRels = append(removedRels, c)
+		}
+	}
+
+	view := &diffView{title: after.Title}
+
+	for _, name := range after.entityNames() {
+		entity := after.Entities[name]
+		de := &diffEntity{entity: entity, name: name, status: entityStatus[name]}
+		for _, attr := range entity.Attributes {
+			de.attributes = append(de.attributes, &diffAttribute{attr: attr, status: attrStatus[name+"."+attr.Name]})
+		}
+
+		oldName := name
+		if from, ok := renamedFrom[name]; ok {
+			oldName = from
+		}
+		if old, ok := before.Entities[oldName]; ok {
+			for _, attrName := range removedAttrs[name] {
+				for _, attr := range old.Attributes {
+					if attr.Name == attrName {
+						de.attributes = append(de.attributes, &diffAttribute{attr: attr, status: statusRemoved})
+					}
+				}
+			}
+		}
+
+		view.entities = append(view.entities, de)
+	}
+
+	for _, name := range removedEntities {
+		entity := before.Entities[name]
+		de := &diffEntity{entity: entity, name: name, status: statusRemoved}
+		for _, attr := range entity.Attributes {
+			de.attributes = append(de.attributes, &diffAttribute{attr: attr})
+		}
+		view.entities = append(view.entities, de)
+	}
+
+	sort.SliceStable(view.entities, func(i, j int) bool {
+		return view.entities[i].name < view.entities[j].name
+	})
+
+	for _, rel := range after.Relationships {
+		view.relationships = append(view.relationships, &diffRelationship{
+			rel:    rel,
+			status: relStatus[relKey(rel.From, rel.To, rel.Field)],
+		})
+	}
+	for _, rel := range removedRelationships(before, removedRels, renamedFrom) {
+		view.relationships = append(view.relationships, &diffRelationship{rel: rel, status: statusRemoved})
+	}
+
+	return view
+}
+
+// removedRelationships finds the relationships of before that the changes
+// report removed, copied with renamed entities under their new names so
+// they keep their participation and label.
+func removedRelationships(before *Diagram, changes []Change, renamedFrom map[string]string) []*Relationship {
+	renames := make(map[string]string, len(renamedFrom))
+	for newName, oldName := range renamedFrom {
+		renames[oldName] = newName
+	}
+	rename := func(name string) string {
+		if to, ok := renames[name]; ok {
+			return to
+		}
+		return name
+	}
+
+	var removed []*Relationship
+	found := make(map[*Relationship]bool, len(changes))
+	for _, c := range changes {
+		for _, rel := range before.Relationships {
+			if found[rel] || rename(rel.From) != c.Entity || rename(rel.To) != c.To ||
+				rel.Field != c.Field || string(rel.Cardinality) != c.Old {
+				continue
+			}
+			found[rel] = true
+			clone := *rel
+			clone.From, clone.To = c.Entity, c.To
+			removed = append(removed, &clone)
+			break
+		}
+	}
+	return removed
+}
+
+// toMermaid renders the diff view as a Mermaid ERD with classDef highlighting.
+func (v *diffView) toMermaid() string {
+	var sb strings.Builder
+
+	sb.WriteString("erDiagram\n")
+
+	for _, de := range v.entities {
+		sb.WriteString(fmt.Sprintf("    %s {\n", sanitizeName(de.name)))
+		for _, da := range de.attributes {
+			sb.WriteString(formatMermaidAttribute(annotateDiffAttribute(da), attributeKeys(de.entity, da.attr), RenderOptions{}))
+		}
+		sb.WriteString("    }\n")
+	}
+
+	for _, dr := range v.relationships {
+		if dr.status == "" {
+			sb.WriteString(formatMermaidRelationship(dr.rel))
+			continue
+		}
+		label := dr.rel.Field
+		if dr.rel.Label != nil {
+			label = *dr.rel.Label
+		}
+		sb.WriteString(fmt.Sprintf("    %s %s %s : \"%s (%s)\"\n",
+			sanitizeName(dr.rel.From),
+			crowsFoot(dr.rel.Participation()),
+			sanitizeName(dr.rel.To),
+			strings.ReplaceAll(label, "\"", "'"),
+			dr.status))
+	}
+
+	for _, status := range []diffStatus{statusAdded, statusRemoved, statusModified} {
+		color := diffColors[status]
+		sb.WriteString(fmt.Sprintf("    classDef %s fill:%s,stroke:%s\n", status, color.fill, color.stroke))
+	}
+	for _, status := range []diffStatus{statusAdded, statusRemoved, statusModified} {
+		var names []string
+		for _, de := range v.entities {
+			if de.status == status {
+				names = append(names, sanitizeName(de.name))
+			}
+		}
+		if len(names) > 0 {
+			sb.WriteString(fmt.Sprintf("    class %s %s\n", strings.Join(names, ","), status))
+		}
+	}
+
+	return sb.String()
+}
+
+// annotateDiffAttribute returns a copy of the attribute whose note leads
+// with its diff status, for formats that cannot color attributes.
+func annotateDiffAttribute(da *diffAttribute) *Attribute {
+	if da.status == "" {
+		return da.attr
+	}
+	attr := *da.attr
+	note := string(da.status)
+	if attr.Note != nil {
+		note += ", " + *attr.Note
+	}
+	attr.Note = &note
+	return &attr
+}
+
+// toDOT renders the diff view as a GraphViz DOT diagram with colored nodes and edges.
+func (v *diffView) toDOT() string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph ERD {\n")
+	sb.WriteString("    rankdir=LR;\n")
+	sb.WriteString("    node [shape=record];\n")
+
+	if v.title != "" {
+		sb.WriteString("    labelloc=\"t\";\n")
+		sb.WriteString(fmt.Sprintf("    label=%q;\n", escapeDOT(v.title)))
+	}
+
+	sb.WriteString("\n")
+
+	rw := &renderWriter{w: &sb}
+	for _, de := range v.entities {
+		// Draw removed attributes alongside the current ones
+		entity := *de.entity
+		entity.Attributes = make([]*Attribute, 0, len(de.attributes))
+		status := make(map[*Attribute]diffStatus, len(de.attributes))
+		for _, da := range de.attributes {
+			entity.Attributes = append(entity.Attributes, da.attr)
+			status[da.attr] = da.status
+		}
+		writeDOTEntity(rw, "    ", de.name, &entity, RenderOptions{}, dotHook{
+			mark: func(attr *Attribute) string {
+				return diffMarkers[status[attr]]
+			},
+			style: formatDOTDiffNodeStyle(de.status),
+		})
+	}
+
+	sb.WriteString("\n")
+
+	for _, dr := range v.relationships {
+		label := dr.rel.Field
+		if dr.rel.Label != nil {
+			label = *dr.rel.Label
+		}
+		sb.WriteString(fmt.Sprintf("    %s -> %s [%s label=%q%s];\n",
+			sanitizeName(dr.rel.From),
+			sanitizeName(dr.rel.To),
+			getDOTEdgeStyle(dr.rel),
+			escapeDOT(label),
+			formatDOTDiffEdgeStyle(dr.status)))
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// formatDOTDiffNodeStyle returns the node attributes for a diff status.
+func formatDOTDiffNodeStyle(status diffStatus) string {
+	color, ok := diffColors[status]
+	if !ok {
+		return ""
+	}
+	style := "filled"
+	if status == statusRemoved {
+		style = "filled,dashed"
+	}
+	return fmt.Sprintf(", color=%q, fillcolor=%q, style=%q", color.stroke, color.fill, style)
+}
+
+// formatDOTDiffEdgeStyle returns the edge attributes for a diff status.
+func formatDOTDiffEdgeStyle(status diffStatus) string {
+	color, ok := diffColors[status]
+	if !ok {
+		return ""
+	}
+	style := "solid"
+	if status == statusRemoved {
+		style = "dashed"
+	}
+	return fmt.Sprintf(", color=%q, fontcolor=%q, style=%q, penwidth=2", color.stroke, color.stroke, style)
+}
@@ -0,0 +1,165 @@
+package erd
+
+import (
+	"strings"
+	"testing"
+)
+
+// testDiffAfter changes testDiffBase in every way RenderDiff highlights.
+func testDiffAfter() *Diagram {
+	after := testDiffBase()
+	user := after.Entities["User"]
+	user.Attributes[1].WithNullable()
+	user.Attributes = append(user.Attributes[:2], NewAttribute("Phone", "string"))
+	after.AddEntity(NewEntity("Shop").AddAttribute(NewAttribute("ID", "string")))
+	after.Relationships[0].Cardinality = ManyToOne
+	after.AddRelationship(NewRelationship("User", "Shop", "Shops", OneToMany))
+	return after
+}
+
+func TestRenderDiff_Mermaid(t *testing.T) {
+	before := testDiffBase()
+	before.AddEntity(NewEntity("Cart").AddAttribute(NewAttribute("Token", "string")))
+	before.AddRelationship(NewRelationship("User", "Cart", "Cart", OneToOne))
+
+	output, err := RenderDiff(before, testDiffAfter(), MermaidFormat)
+	if err != nil {
+		t.Fatalf("RenderDiff() unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"erDiagram\n",
+		"    Cart {\n        string Token\n    }\n",
+		"        string ID PK\n",
+		`        string Email "nullable, modified"`,
+		`        string Phone "added"`,
+		`        int Age "removed"`,
+		"    Order }o--|| User : \"User (modified)\"\n",
+		"    User ||--o{ Shop : \"Shops (added)\"\n",
+		"    User ||--|| Cart : \"Cart (removed)\"\n",
+		"    classDef added fill:#c8e6c9,stroke:#2e7d32\n",
+		"    classDef removed fill:#ffcdd2,stroke:#c62828\n",
+		"    classDef modified fill:#ffe0b2,stroke:#ff8f00\n",
+		"    class Shop added\n",
+		"    class Cart removed\n",
+		"    class User modified\n",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("RenderDiff() output should contain %q, got:\n%s", exp, output)
+		}
+	}
+
+	// Unchanged entities carry no class
+	if strings.Contains(output, "class Order") {
+		t.Errorf("unchanged entity should not be classed, got:\n%s", output)
+	}
+}
+
+func TestRenderDiff_DOT(t *testing.T) {
+	before := testDiffBase()
+	before.AddEntity(NewEntity("Cart").AddAttribute(NewAttribute("Token", "string")))
+
+	output, err := RenderDiff(before, testDiffAfter(), DOTFormat)
+	if err != nil {
+		t.Fatalf("RenderDiff() unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"digraph ERD {\n",
+		`label="Shop";`,
+		`Cart [label="{Cart|Token: string\l}", color="#c62828", fillcolor="#ffcdd2", style="filled,dashed"];`,
+		`Shop [label="{Shop|ID: string\l}", color="#2e7d32", fillcolor="#c8e6c9", style="filled"];`,
+		`User [label="{User|PK ID: string\l~ Email: string ?\l+ Phone: string\l- Age: int\l}", color="#ff8f00", fillcolor="#ffe0b2", style="filled"];`,
+		`Order [label="{Order|PK ID: string\lFK UserID: string\l}"];`,
+		`Order -> User [arrowhead=normal, arrowtail=crow, dir=both label="User", color="#ff8f00", fontcolor="#ff8f00", style="solid", penwidth=2];`,
+		`User -> Shop [arrowhead=crow, arrowtail=normal, dir=both label="Shops", color="#2e7d32", fontcolor="#2e7d32", style="solid", penwidth=2];`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("RenderDiff() output should contain %q, got:\n%s", exp, output)
+		}
+	}
+}
+
+func TestRenderDiff_RemovedRelationship(t *testing.T) {
+	// Removed relationships keep their participation and label
+	before := testDiffBase()
+	before.AddEntity(NewEntity("Cart").AddAttribute(NewAttribute("Token", "string")))
+	before.AddRelationship(NewRelationship("User", "Cart", "Cart", OneToOne).
+		WithLabel("fills").
+		WithToParticipation(ZeroOrOne))
+	after := testDiffBase()
+	after.AddEntity(NewEntity("Cart").AddAttribute(NewAttribute("Token", "string")))
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{MermaidFormat, "    User ||--o| Cart : \"fills (removed)\"\n"},
+		{DOTFormat, `User -> Cart [arrowhead=teeodot, arrowtail=teetee, dir=both label="fills", color="#c62828", fontcolor="#c62828", style="dashed", penwidth=2];`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			output, err := RenderDiff(before, after, tt.format)
+			if err != nil {
+				t.Fatalf("RenderDiff() unexpected error: %v", err)
+			}
+			if !strings.Contains(output, tt.want) {
+				t.Errorf("RenderDiff() output should contain %q, got:\n%s", tt.want, output)
+			}
+		})
+	}
+}
+
+func TestRenderDiff_Unchanged(t *testing.T) {
+	d := testDiffBase()
+
+	mermaid, err := RenderDiff(d, testDiffBase(), MermaidFormat)
+	if err != nil {
+		t.Fatalf("RenderDiff() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(mermaid, d.ToMermaid()) {
+		t.Errorf("unchanged diagram should render like ToMermaid, got:\n%s", mermaid)
+	}
+	if strings.Contains(mermaid, "class ") {
+		t.Errorf("unchanged diagram should not class entities, got:\n%s", mermaid)
+	}
+
+	dot, err := RenderDiff(d, testDiffBase(), DOTFormat)
+	if err != nil {
+		t.Fatalf("RenderDiff() unexpected error: %v", err)
+	}
+	if dot != d.ToDOT() {
+		t.Errorf("unchanged diagram should render like ToDOT:\ngot:\n%s\nwant:\n%s", dot, d.ToDOT())
+	}
+}
+
+func TestRenderDiff_Renamed(t *testing.T) {
+	after := testDiffBase()
+	user := after.Entities["User"]
+	delete(after.Entities, "User")
+	user.Name = "Customer"
+	after.AddEntity(user)
+	after.Relationships[0].To = "Customer"
+
+	output, err := RenderDiff(testDiffBase(), after, MermaidFormat)
+	if err != nil {
+		t.Fatalf("RenderDiff() unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "class Customer modified") {
+		t.Errorf("renamed entity should be modified, got:\n%s", output)
+	}
+	if strings.Contains(output, "User {") || strings.Contains(output, "(removed)") {
+		t.Errorf("renamed entity should not appear as removed, got:\n%s", output)
+	}
+}
+
+func TestRenderDiff_UnsupportedFormat(t *testing.T) {
+	for _, format := range []Format{PlantUMLFormat, DBMLFormat, "svg"} {
+		if _, err := RenderDiff(testDiffBase(), testDiffAfter(), format); err == nil {
+			t.Errorf("RenderDiff(%q) expected error", format)
+		}
+	}
+}
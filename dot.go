@@ -2,105 +2,235 @@ package erd
 
 import (
 	"fmt"
-	"sort"
+	"html"
+	"io"
+	"slices"
 	"strings"
 )
 
 // ToDOT generates a GraphViz DOT diagram from the diagram structure.
 func (d *Diagram) ToDOT() string {
+	return d.ToDOTWith(RenderOptions{})
+}
+
+// ToDOTWith generates a GraphViz DOT diagram using the given options.
+func (d *Diagram) ToDOTWith(opts RenderOptions) string {
 	var sb strings.Builder
+	_ = d.WriteDOTWith(&sb, opts) // strings.Builder never fails
+	return sb.String()
+}
+
+// WriteDOT streams a GraphViz DOT diagram to w.
+func (d *Diagram) WriteDOT(w io.Writer) error {
+	return d.WriteDOTWith(w, RenderOptions{})
+}
 
-	sb.WriteString("digraph ERD {\n")
-	sb.WriteString("    rankdir=LR;\n")
-	sb.WriteString("    node [shape=record];\n")
+// WriteDOTWith streams a GraphViz DOT diagram to w using the given options.
+// Entities and relationships are written one at a time, so memory use does
+// not grow with the size of the output. It returns the first write error,
+// after which nothing more is written.
+func (d *Diagram) WriteDOTWith(w io.Writer, opts RenderOptions) error {
+	rw := &renderWriter{w: w}
+
+	rw.WriteString("digraph ERD {\n")
+	rw.WriteString(fmt.Sprintf("    rankdir=%s;\n", opts.rankDir()))
+	if opts.HTMLTables {
+		rw.WriteString("    node [shape=plaintext];\n")
+	} else {
+		rw.WriteString("    node [shape=record];\n")
+	}
 
 	// Add title if present
-	if d.Title != "" {
-		sb.WriteString("    labelloc=\"t\";\n")
-		sb.WriteString(fmt.Sprintf("    label=%q;\n", escapeDOT(d.Title)))
+	if d.Title != "" && !opts.HideTitle {
+		rw.WriteString("    labelloc=\"t\";\n")
+		rw.WriteString(fmt.Sprintf("    label=%q;\n", escapeDOT(d.Title)))
 	}
 
-	sb.WriteString("\n")
+	rw.WriteString("\n")
 
 	// Write entities with their attributes (sorted by name for deterministic output)
-	entityNames := make([]string, 0, len(d.Entities))
-	for name := range d.Entities {
-		entityNames = append(entityNames, name)
+	ungrouped, groups := d.entityGroups(opts)
+	for _, name := range ungrouped {
+		writeDOTEntity(rw, "    ", name, d.Entities[name], opts, dotHook{})
 	}
-	sort.Strings(entityNames)
-
-	for _, name := range entityNames {
-		entity := d.Entities[name]
-		sb.WriteString(formatDOTEntity(entity))
+	for _, group := range groups {
+		writeDOTCluster(rw, d, group, opts)
 	}
 
-	sb.WriteString("\n")
+	rw.WriteString("\n")
 
 	// Write relationships
 	for _, rel := range d.Relationships {
-		sb.WriteString(formatDOTRelationship(rel))
+		if opts.HTMLTables {
+			rw.WriteString(formatDOTEdge(
+				dotPort(d, rel.From, rel.FromAttributes, opts),
+				dotPort(d, rel.To, rel.ToAttributes, opts),
+				rel))
+		} else {
+			rw.WriteString(formatDOTRelationship(rel))
+		}
 	}
 
-	sb.WriteString("}\n")
-	return sb.String()
+	rw.WriteString("}\n")
+	return rw.err
 }
 
-// formatDOTEntity formats an entity as a DOT record node.
-func formatDOTEntity(entity *Entity) string {
-	var sb strings.Builder
+// dotHook decorates the record nodes drawn by writeDOTEntity: mark
+// prefixes each attribute and style is appended to the node attributes.
+// RenderDiff uses it to highlight changes; the zero value draws plain nodes.
+type dotHook struct {
+	mark  func(attr *Attribute) string
+	style string
+}
+
+// writeDOTEntity writes an entity as a DOT record node identified by its
+// key, one attribute at a time. The indent nests nodes inside clusters.
+func writeDOTEntity(rw *renderWriter, indent, name string, entity *Entity, opts RenderOptions, hook dotHook) {
+	if opts.HTMLTables {
+		writeDOTTable(rw, indent, name, entity, opts)
+		return
+	}
+	if opts.HideAttributes {
+		rw.WriteString(fmt.Sprintf("%s%s [label=\"{%s}\"%s];\n", indent, sanitizeName(name), escapeDOT(name), hook.style))
+		return
+	}
 
-	sb.WriteString(fmt.Sprintf("    %s [label=\"{%s|",
-		sanitizeName(entity.Name),
-		escapeDOT(entity.Name)))
+	rw.WriteString(fmt.Sprintf("%s%s [label=\"{%s|",
+		indent,
+		sanitizeName(name),
+		escapeDOT(name)))
 
-	attrs := make([]string, 0, len(entity.Attributes))
-	for _, attr := range entity.Attributes {
-		attrs = append(attrs, formatDOTAttribute(attr))
+	for i, attr := range opts.attributes(entity) {
+		if i > 0 {
+			rw.WriteString("\\l")
+		}
+		if hook.mark != nil {
+			rw.WriteString(escapeDOT(hook.mark(attr)))
+		}
+		rw.WriteString(formatDOTAttribute(attr, attributeKeys(entity, attr), opts))
 	}
 
-	sb.WriteString(strings.Join(attrs, "\\l"))
-	sb.WriteString("\\l}\"];\n")
+	rw.WriteString("\\l}\"" + hook.style + "];\n")
+}
 
-	return sb.String()
+// writeDOTTable writes an entity as an HTML-like table with a row per
+// attribute, each a port named after the attribute.
+func writeDOTTable(rw *renderWriter, indent, name string, entity *Entity, opts RenderOptions) {
+	rw.WriteString(fmt.Sprintf("%s%s [label=<\n", indent, sanitizeName(name)))
+	rw.WriteString(fmt.Sprintf("%s    <TABLE BORDER=\"0\" CELLBORDER=\"1\" CELLSPACING=\"0\">\n", indent))
+	rw.WriteString(fmt.Sprintf("%s    <TR><TD BGCOLOR=\"lightgrey\"><B>%s</B></TD></TR>\n", indent, escapeDOTHTML(name)))
+	for _, attr := range opts.attributes(entity) {
+		rw.WriteString(fmt.Sprintf("%s    %s\n", indent, formatDOTTableRow(attr, attributeKeys(entity, attr), opts)))
+	}
+	rw.WriteString(fmt.Sprintf("%s    </TABLE>\n", indent))
+	rw.WriteString(fmt.Sprintf("%s>];\n", indent))
 }
 
-// formatDOTAttribute formats an attribute for DOT syntax.
-func formatDOTAttribute(attr *Attribute) string {
+// formatDOTTableRow formats an attribute as an HTML-like table row, bold for
+// primary keys and italic when nullable.
+func formatDOTTableRow(attr *Attribute, keys []KeyType, opts RenderOptions) string {
+	text := escapeDOTHTML(dotAttributeText(attr, keys, opts))
+	if attr.Nullable {
+		text = "<I>" + text + "</I>"
+	}
+	if slices.Contains(keys, PrimaryKey) {
+		text = "<B>" + text + "</B>"
+	}
+	return fmt.Sprintf("<TR><TD PORT=\"%s\" ALIGN=\"LEFT\">%s</TD></TR>",
+		escapeDOTHTML(sanitizeName(attr.Name)),
+		text)
+}
+
+// dotPort returns the DOT node an edge attaches to: the row of the first
+// joined attribute, such as Order:UserID, when it is drawn as a table row,
+// and the whole entity otherwise.
+func dotPort(d *Diagram, key string, attrs []string, opts RenderOptions) string {
+	node := sanitizeName(key)
+	entity, ok := d.Entities[key]
+	if !ok || len(attrs) == 0 {
+		return node
+	}
+	for _, attr := range opts.attributes(entity) {
+		if attr.Name == attrs[0] {
+			return node + ":" + sanitizeName(attr.Name)
+		}
+	}
+	return node
+}
+
+// writeDOTCluster writes a package group as a labeled cluster subgraph.
+func writeDOTCluster(rw *renderWriter, d *Diagram, group packageGroup, opts RenderOptions) {
+	rw.WriteString(fmt.Sprintf("    subgraph cluster_%s {\n", sanitizeName(group.name)))
+	rw.WriteString(fmt.Sprintf("        label=%q;\n", escapeDOT(group.name)))
+	for _, name := range group.keys {
+		writeDOTEntity(rw, "        ", name, d.Entities[name], opts, dotHook{})
+	}
+	rw.WriteString("    }\n")
+}
+
+// formatDOTAttribute formats an attribute and its keys for DOT syntax.
+func formatDOTAttribute(attr *Attribute, keys []KeyType, opts RenderOptions) string {
+	return escapeDOTRecord(escapeDOT(dotAttributeText(attr, keys, opts)))
+}
+
+// dotAttributeText returns the unescaped text drawn for an attribute, such
+// as "PK, FK UserID: string ?".
+func dotAttributeText(attr *Attribute, keys []KeyType, opts RenderOptions) string {
 	var parts []string
 
-	// Add key indicator
-	if attr.Key != nil {
-		parts = append(parts, string(*attr.Key))
+	// Add key indicators, such as "PK, FK"
+	if len(keys) > 0 {
+		parts = append(parts, keyLabel(keys))
 	}
 
 	// Add name and type
-	parts = append(parts, fmt.Sprintf("%s: %s",
-		attr.Name,
-		sanitizeType(attr.Type)))
+	if opts.HideTypes {
+		parts = append(parts, attr.Name)
+	} else {
+		parts = append(parts, fmt.Sprintf("%s: %s",
+			attr.Name,
+			opts.typeName(attr.Type)))
+	}
 
 	// Add nullable indicator
 	if attr.Nullable {
 		parts = append(parts, "?")
 	}
 
-	return escapeDOT(strings.Join(parts, " "))
+	return strings.Join(parts, " ")
 }
 
 // formatDOTRelationship formats a relationship for DOT syntax.
 func formatDOTRelationship(rel *Relationship) string {
-	edgeStyle := getDOTCardinality(rel.Cardinality)
+	return formatDOTEdge(sanitizeName(rel.From), sanitizeName(rel.To), rel)
+}
+
+// formatDOTEdge formats a relationship as an edge between two DOT nodes or
+// ports.
+func formatDOTEdge(from, to string, rel *Relationship) string {
+	edgeStyle := getDOTEdgeStyle(rel)
 	label := rel.Field
 	if rel.Label != nil {
 		label = *rel.Label
 	}
 
 	return fmt.Sprintf("    %s -> %s [%s label=%q];\n",
-		sanitizeName(rel.From),
-		sanitizeName(rel.To),
+		from,
+		to,
 		edgeStyle,
 		escapeDOT(label))
 }
 
+// getDOTEdgeStyle returns DOT edge styling for a relationship: crow's foot
+// arrows when it sets participation, and arrows for its cardinality
+// otherwise.
+func getDOTEdgeStyle(rel *Relationship) string {
+	if rel.hasParticipation() {
+		return getDOTParticipation(rel.Participation())
+	}
+	return getDOTCardinality(rel.Cardinality)
+}
+
 // getDOTCardinality returns DOT edge styling for cardinality.
 func getDOTCardinality(c Cardinality) string {
 	switch c {
@@ -117,6 +247,21 @@ func getDOTCardinality(c Cardinality) string {
 	}
 }
 
+// escapeDOTRecord escapes characters that structure record labels, such as
+// the braces in interface{}. It is applied after escapeDOT.
+func escapeDOTRecord(s string) string {
+	return dotRecordEscaper.Replace(s)
+}
+
+// dotRecordEscaper backslash-escapes record label field delimiters.
+var dotRecordEscaper = strings.NewReplacer("{", "\\{", "}", "\\}", "|", "\\|", "<", "\\<", ">", "\\>")
+
+// escapeDOTHTML escapes text for HTML-like labels, where &, <, > and quotes
+// are markup but braces and bars, which structure record labels, are not.
+func escapeDOTHTML(s string) string {
+	return html.EscapeString(s)
+}
+
 // escapeDOT escapes special characters for DOT syntax.
 func escapeDOT(s string) string {
 	s = strings.ReplaceAll(s, "\\", "\\\\")
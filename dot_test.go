@@ -1,6 +1,7 @@
 package erd
 
 import (
+	"io"
 	"strings"
 	"testing"
 )
@@ -37,9 +38,41 @@ func TestFormatDOTRelationship_WithLabel(t *testing.T) {
 	}
 }
 
+func TestFormatDOTRelationship_Participation(t *testing.T) {
+	tests := []struct {
+		name string
+		rel  *Relationship
+		want string
+	}{
+		{
+			"cardinality only",
+			NewRelationship("User", "Post", "Posts", OneToMany),
+			"    User -> Post [arrowhead=crow, arrowtail=normal, dir=both label=\"Posts\"];\n",
+		},
+		{
+			"optional to end",
+			NewRelationship("User", "Profile", "Profile", OneToOne).WithToParticipation(ZeroOrOne),
+			"    User -> Profile [arrowhead=teeodot, arrowtail=teetee, dir=both label=\"Profile\"];\n",
+		},
+		{
+			"required many",
+			NewRelationship("Order", "LineItem", "Items", OneToMany).WithToParticipation(OneOrMore),
+			"    Order -> LineItem [arrowhead=crowtee, arrowtail=teetee, dir=both label=\"Items\"];\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDOTRelationship(tt.rel); got != tt.want {
+				t.Errorf("formatDOTRelationship() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFormatDOTAttribute_WithNullable(t *testing.T) {
 	attr := NewAttribute("Name", "string").WithNullable()
-	output := formatDOTAttribute(attr)
+	output := formatDOTAttribute(attr, attributeKeys(nil, attr), RenderOptions{})
 
 	if !strings.Contains(output, "?") {
 		t.Errorf("formatDOTAttribute() should contain '?' for nullable, got %q", output)
@@ -48,7 +81,7 @@ func TestFormatDOTAttribute_WithNullable(t *testing.T) {
 
 func TestFormatDOTAttribute_WithoutKey(t *testing.T) {
 	attr := NewAttribute("Name", "string")
-	output := formatDOTAttribute(attr)
+	output := formatDOTAttribute(attr, attributeKeys(nil, attr), RenderOptions{})
 
 	// Should not start with PK/FK/UK
 	if strings.HasPrefix(output, "PK") || strings.HasPrefix(output, "FK") || strings.HasPrefix(output, "UK") {
@@ -81,6 +114,37 @@ func TestEscapeDOT(t *testing.T) {
 	}
 }
 
+func TestEscapeDOTRecord(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"braces", "map[string]interface{}", `map[string]interface\{\}`},
+		{"bar", "a|b", `a\|b`},
+		{"angle brackets", "Set<T>", `Set\<T\>`},
+		{"no special chars", "ID: string", "ID: string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeDOTRecord(tt.input); got != tt.want {
+				t.Errorf("escapeDOTRecord() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToDOT_RecordDelimitersInTypes(t *testing.T) {
+	diagram := NewDiagram("Test").
+		AddEntity(NewEntity("User").AddAttribute(NewAttribute("Meta", "interface{}")))
+
+	exp := `User [label="{User|Meta: interface\{\}\l}"];`
+	if output := diagram.ToDOT(); !strings.Contains(output, exp) {
+		t.Errorf("ToDOT() should contain %q, got:\n%s", exp, output)
+	}
+}
+
 func TestToDOT_WithEmptyTitle(t *testing.T) {
 	diagram := &Diagram{
 		Title:    "",
@@ -98,3 +162,143 @@ func TestToDOT_WithEmptyTitle(t *testing.T) {
 		t.Error("ToDOT() should not include title label for empty title")
 	}
 }
+
+func TestDiagram_WriteDOT(t *testing.T) {
+	d := testPackagedDiagram()
+
+	var sb strings.Builder
+	if err := d.WriteDOT(&sb); err != nil {
+		t.Fatalf("WriteDOT() error = %v", err)
+	}
+	if sb.String() != d.ToDOT() {
+		t.Errorf("WriteDOT() = %q, want %q", sb.String(), d.ToDOT())
+	}
+
+	sb.Reset()
+	opts := RenderOptions{GroupByPackage: true}
+	if err := d.WriteDOTWith(&sb, opts); err != nil {
+		t.Fatalf("WriteDOTWith() error = %v", err)
+	}
+	if sb.String() != d.ToDOTWith(opts) {
+		t.Errorf("WriteDOTWith() = %q, want %q", sb.String(), d.ToDOTWith(opts))
+	}
+}
+
+func TestDiagram_WriteDOT_WriteError(t *testing.T) {
+	w := &limitedWriter{n: 3}
+	if err := testPackagedDiagram().WriteDOT(w); err == nil {
+		t.Fatal("WriteDOT() expected error from writer")
+	}
+	if w.written.String() != "digraph ERD {\n    rankdir=LR;\n    node [shape=record];\n" {
+		t.Errorf("WriteDOT() should stop after the failed write, got:\n%s", w.written.String())
+	}
+}
+
+func BenchmarkToDOT(b *testing.B) {
+	d := benchmarkDiagram(5000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = d.ToDOT()
+	}
+}
+
+func BenchmarkWriteDOT(b *testing.B) {
+	d := benchmarkDiagram(5000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := d.WriteDOT(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestToDOT_CompositeKeys(t *testing.T) {
+	output := testCompositeDiagram().ToDOT()
+
+	exp := `LineItem [label="{LineItem|PK, FK TenantID: string\lPK, FK OrderID: string\lPK SKU: string\l}"];`
+	if !strings.Contains(output, exp) {
+		t.Errorf("ToDOT() should contain %q, got:\n%s", exp, output)
+	}
+}
+
+func TestToDOTWith_HTMLTables(t *testing.T) {
+	d := NewDiagram("Shop").
+		AddEntity(NewEntity("User").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("Meta", "map[string]interface{}").WithNullable()).
+			AddAttribute(NewAttribute("Tags", "Set<a|b>"))).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("UserID", "string").WithForeignKey())).
+		AddRelationship(NewRelationship("Order", "User", "Buyer", ManyToOne).
+			WithAttributes([]string{"UserID"}, []string{"ID"})).
+		AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany))
+
+	tests := []struct {
+		name string
+		want []string
+		opts RenderOptions
+	}{
+		{
+			name: "attribute ports",
+			opts: RenderOptions{HTMLTables: true},
+			want: []string{
+				"    node [shape=plaintext];\n",
+				"    User [label=<\n        <TABLE BORDER=\"0\" CELLBORDER=\"1\" CELLSPACING=\"0\">\n        <TR><TD BGCOLOR=\"lightgrey\"><B>User</B></TD></TR>\n",
+				`<TR><TD PORT="ID" ALIGN="LEFT"><B>PK ID: string</B></TD></TR>`,
+				`<TR><TD PORT="Meta" ALIGN="LEFT"><I>Meta: map[string]interface{} ?</I></TD></TR>`,
+				`<TR><TD PORT="Tags" ALIGN="LEFT">Tags: Set&lt;a|b&gt;</TD></TR>`,
+				"        </TABLE>\n    >];\n",
+				"    Order:UserID -> User:ID [arrowhead=normal, arrowtail=crow, dir=both label=\"Buyer\"];\n",
+				"    User -> Order [arrowhead=crow, arrowtail=normal, dir=both label=\"Orders\"];\n",
+			},
+		},
+		{
+			name: "hidden attributes have no ports",
+			opts: RenderOptions{HTMLTables: true, HideAttributes: true},
+			want: []string{
+				"    User [label=<\n        <TABLE BORDER=\"0\" CELLBORDER=\"1\" CELLSPACING=\"0\">\n        <TR><TD BGCOLOR=\"lightgrey\"><B>User</B></TD></TR>\n        </TABLE>\n    >];\n",
+				"    Order -> User [arrowhead=normal, arrowtail=crow, dir=both label=\"Buyer\"];\n",
+			},
+		},
+		{
+			name: "keys only keeps key ports",
+			opts: RenderOptions{HTMLTables: true, KeysOnly: true},
+			want: []string{
+				"    Order:UserID -> User:ID [",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := d.ToDOTWith(tt.opts)
+			for _, w := range tt.want {
+				if !strings.Contains(output, w) {
+					t.Errorf("ToDOTWith() should contain %q, got:\n%s", w, output)
+				}
+			}
+			if strings.Contains(output, "Meta") && tt.opts.KeysOnly {
+				t.Errorf("ToDOTWith() should not draw non-key rows, got:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestEscapeDOTHTML(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"interface{}", "interface{}"},
+		{"a|b", "a|b"},
+		{"Set<T>", "Set&lt;T&gt;"},
+		{`"a" & 'b'`, "&#34;a&#34; &amp; &#39;b&#39;"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeDOTHTML(tt.input); got != tt.want {
+			t.Errorf("escapeDOTHTML(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package erd
+
+import (
+	"slices"
+	"strings"
+)
+
+// linkForeignKeys records the attributes joined by foreign keys on the
+// relationships between their entities, adding a relationship from the
+// entity holding the foreign key where none exists. Foreign keys declared
+// with a reference are always linked; with conventions, so are attributes
+// named after another entity's primary key, such as UserID or user_id, which
+// gain a foreign key group referencing it.
+func (d *Diagram) linkForeignKeys(conventions bool) {
+	for _, name := range d.entityNames() {
+		holder := d.Entities[name]
+		linked := make(map[string]bool)
+
+		for _, key := range holder.Keys {
+			if key.Type != ForeignKey || key.Reference == nil || len(key.Attributes) == 0 {
+				continue
+			}
+			target, ok := d.resolveEntity(key.Reference.Entity)
+			if !ok {
+				continue
+			}
+			refs := referencedColumns(d.Entities[target], key)
+			if len(refs) != len(key.Attributes) {
+				continue
+			}
+			d.linkForeignKey(name, target, key.Attributes, refs)
+			for _, attr := range key.Attributes {
+				linked[attr] = true
+			}
+		}
+
+		if !conventions {
+			continue
+		}
+		for _, attr := range holder.Attributes {
+			if linked[attr.Name] {
+				continue
+			}
+			target := d.entityForKeyName(attr.Name)
+			if target == "" || target == name {
+				continue
+			}
+			pk := primaryKey(d.Entities[target])
+			if len(pk) != 1 {
+				continue
+			}
+			// The foreign key is recorded as a key group rather than on the
+			// attribute, which keeps any key the attribute already declares
+			if !hasKey(holder, attr, ForeignKey) {
+				holder.AddKey(NewKey(tagKeyName(ForeignKey, attr.Name), ForeignKey, attr.Name).WithReference(target, pk[0].Name))
+			}
+			d.linkForeignKey(name, target, []string{attr.Name}, []string{pk[0].Name})
+		}
+	}
+}
+
+// linkForeignKey records a foreign key from holder to target on the first
+// relationship between them that can carry it and has no attributes yet,
+// or else adds a many-to-one relationship named after the foreign key, or
+// one-to-one if the foreign key is unique.
+func (d *Diagram) linkForeignKey(holder, target string, attrs, refs []string) {
+	for _, rel := range d.Relationships {
+		if len(rel.FromAttributes) > 0 || len(rel.ToAttributes) > 0 {
+			continue
+		}
+		switch {
+		case rel.From == holder && rel.To == target && (rel.Cardinality == ManyToOne || rel.Cardinality == OneToOne):
+			rel.WithAttributes(attrs, refs)
+			return
+		case rel.From == target && rel.To == holder && (rel.Cardinality == OneToMany || rel.Cardinality == OneToOne):
+			rel.WithAttributes(refs, attrs)
+			return
+		}
+	}
+
+	cardinality := ManyToOne
+	if isUniqueKey(d.Entities[holder], attrs) {
+		cardinality = OneToOne
+	}
+	d.AddRelationship(NewRelationship(holder, target, strings.Join(attrs, ","), cardinality).WithAttributes(attrs, refs))
+}
+
+// isUniqueKey reports whether the attributes are exactly the entity's
+// primary key or one of its unique keys.
+func isUniqueKey(entity *Entity, attrs []string) bool {
+	if isPrimaryKey(entity, attrs) {
+		return true
+	}
+	if len(attrs) == 1 {
+		if attr := attributeNamed(entity, attrs[0]); attr != nil && attr.Key != nil && *attr.Key == UniqueKey {
+			return true
+		}
+	}
+	return slices.ContainsFunc(entity.Keys, func(key *Key) bool {
+		return key.Type == UniqueKey && slices.Equal(key.Attributes, attrs)
+	})
+}
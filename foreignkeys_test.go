@@ -0,0 +1,212 @@
+package erd
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/zoobzio/sentinel"
+)
+
+// testForeignKeySchema describes Account, Invoice and Receipt types as
+// sentinel would, without scanning them into its global registry. Invoice
+// holds an Account pointer, an fk-tagged AccountID and a Payer referencing
+// Account.ID; Receipt holds an untagged InvoiceID.
+func testForeignKeySchema() map[string]sentinel.Metadata {
+	const pkg = "github.com/app/billing"
+	field := func(name, tag string) sentinel.FieldMetadata {
+		f := sentinel.FieldMetadata{Name: name, Type: "string", Kind: sentinel.KindScalar, Tags: map[string]string{}}
+		if tag != "" {
+			f.Tags["erd"] = tag
+		}
+		return f
+	}
+
+	schema := make(map[string]sentinel.Metadata)
+	for _, meta := range []sentinel.Metadata{
+		{
+			TypeName: "Account",
+			Fields:   []sentinel.FieldMetadata{field("ID", "pk")},
+		},
+		{
+			TypeName: "Invoice",
+			Fields: []sentinel.FieldMetadata{
+				field("ID", "pk"),
+				field("AccountID", "fk"),
+				field("Payer", "fk:Account.ID"),
+				{Name: "Account", Type: "*Account", Kind: sentinel.KindPointer},
+			},
+			Relationships: []sentinel.TypeRelationship{
+				{From: pkg + ".Invoice", To: pkg + ".Account", Field: "Account", Kind: sentinel.RelationshipReference},
+			},
+		},
+		{
+			TypeName: "Receipt",
+			Fields:   []sentinel.FieldMetadata{field("ID", "pk"), field("InvoiceID", "")},
+		},
+	} {
+		meta.FQDN = pkg + "." + meta.TypeName
+		meta.PackageName = pkg
+		schema[meta.FQDN] = meta
+	}
+	return schema
+}
+
+// joinedAttributes summarizes the relationships of a diagram and the
+// attributes they join.
+func joinedAttributes(d *Diagram) []string {
+	var joins []string
+	for _, rel := range d.Relationships {
+		joins = append(joins, fmt.Sprintf("%s%v -> %s%v %s", rel.From, rel.FromAttributes, rel.To, rel.ToAttributes, rel.Cardinality))
+	}
+	return joins
+}
+
+func TestFromSchemaWith_ForeignKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+		opts SchemaOptions
+	}{
+		{
+			name: "declared references only",
+			want: []string{
+				"Invoice[Payer] -> Account[ID] one-to-one",
+			},
+		},
+		{
+			name: "inferred from names",
+			opts: SchemaOptions{InferForeignKeys: true},
+			want: []string{
+				"Invoice[Payer] -> Account[ID] one-to-one",
+				"Invoice[AccountID] -> Account[ID] many-to-one",
+				"Receipt[InvoiceID] -> Invoice[ID] many-to-one",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := FromSchemaWith("Billing", testForeignKeySchema(), tt.opts)
+			if got := joinedAttributes(d); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("relationships = %q, want %q", got, tt.want)
+			}
+			if errs := d.Validate(); len(errs) != 0 {
+				t.Errorf("Validate() unexpected errors: %v", errs)
+			}
+		})
+	}
+}
+
+func TestDiagram_LinkForeignKeys(t *testing.T) {
+	users := func() *Diagram {
+		return NewDiagram("Shop").
+			AddEntity(NewEntity("User").
+				AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+			AddEntity(NewEntity("Order").
+				AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+				AddAttribute(NewAttribute("user_id", "string")))
+	}
+
+	tests := []struct {
+		diagram     *Diagram
+		name        string
+		want        []string
+		conventions bool
+	}{
+		{
+			name:    "conventions off",
+			diagram: users(),
+		},
+		{
+			name:        "snake case name adds relationship",
+			diagram:     users(),
+			conventions: true,
+			want:        []string{"Order[user_id] -> User[ID] many-to-one"},
+		},
+		{
+			name:        "existing relationship records attributes",
+			diagram:     users().AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany)),
+			conventions: true,
+			want:        []string{"User[ID] -> Order[user_id] one-to-many"},
+		},
+		{
+			name: "unique foreign key is one-to-one",
+			diagram: NewDiagram("Shop").
+				AddEntity(NewEntity("User").
+					AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+				AddEntity(NewEntity("Profile").
+					AddAttribute(NewAttribute("UserID", "string").WithPrimaryKey())),
+			conventions: true,
+			want:        []string{"Profile[UserID] -> User[ID] one-to-one"},
+		},
+		{
+			name:    "composite keys",
+			diagram: testCompositeDiagram(),
+			want: []string{
+				"Order[TenantID ID] -> LineItem[TenantID OrderID] one-to-many",
+				"Order[TenantID] -> Tenant[ID] many-to-one",
+			},
+		},
+		{
+			name: "self reference by name is ignored",
+			diagram: NewDiagram("Tree").
+				AddEntity(NewEntity("Node").
+					AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+					AddAttribute(NewAttribute("NodeID", "string"))),
+			conventions: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.diagram.linkForeignKeys(tt.conventions)
+			if got := joinedAttributes(tt.diagram); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("relationships = %q, want %q", got, tt.want)
+			}
+			if errs := tt.diagram.Validate(); len(errs) != 0 {
+				t.Errorf("Validate() unexpected errors: %v", errs)
+			}
+		})
+	}
+}
+
+func TestDiagram_LinkForeignKeys_Keys(t *testing.T) {
+	d := NewDiagram("Shop").
+		AddEntity(NewEntity("User").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Membership").
+			AddAttribute(NewAttribute("UserID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("GroupID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("InviterID", "string")))
+	d.linkForeignKeys(true)
+
+	// An attribute already holding another key gains a foreign key group
+	membership := d.Entities["Membership"]
+	if got := keyLabel(attributeKeys(membership, attributeNamed(membership, "UserID"))); got != "PK, FK" {
+		t.Errorf("UserID keys = %q, want %q", got, "PK, FK")
+	}
+	// Attributes not named after an entity are left alone
+	if attr := attributeNamed(membership, "InviterID"); attr.Key != nil {
+		t.Errorf("InviterID key = %v, want none", *attr.Key)
+	}
+
+	if attr := attributeNamed(membership, "UserID"); attr.Key == nil || *attr.Key != PrimaryKey {
+		t.Errorf("UserID should keep its own key, got %+v", attr)
+	}
+
+	// A plain attribute gains a key group too; the attribute is not modified
+	orders := NewDiagram("Shop").
+		AddEntity(NewEntity("User").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("UserID", "string")))
+	orders.linkForeignKeys(true)
+	order := orders.Entities["Order"]
+	if attr := attributeNamed(order, "UserID"); attr.Key != nil {
+		t.Errorf("UserID key = %v, want none on the attribute", *attr.Key)
+	}
+	if len(order.Keys) != 1 || order.Keys[0].Name != "fk_UserID" || order.Keys[0].Reference == nil || order.Keys[0].Reference.Entity != "User" {
+		t.Errorf("Order keys = %+v, want fk_UserID referencing User", order.Keys)
+	}
+}
@@ -0,0 +1,124 @@
+package erd
+
+import (
+	"fmt"
+	"sort"
+)
+
+// QualifiedName returns the entity's package-qualified name, such as
+// "github.com/app/billing.Account", or its bare name if it has no package.
+func (e *Entity) QualifiedName() string {
+	if e.Package == nil || *e.Package == "" {
+		return e.Name
+	}
+	return *e.Package + "." + e.Name
+}
+
+// Entity looks up an entity by its key in [Diagram.Entities], its qualified
+// name, or its bare name. A bare name shared by entities from several
+// packages is ambiguous and returns nil.
+func (d *Diagram) Entity(name string) *Entity {
+	if key, ok := d.resolveEntity(name); ok {
+		return d.Entities[key]
+	}
+	return nil
+}
+
+// resolveEntity finds the key of the entity a name refers to.
+func (d *Diagram) resolveEntity(name string) (string, bool) {
+	if _, ok := d.Entities[name]; ok {
+		return name, true
+	}
+	matches := entitiesNamed(d.Entities, name)
+	if len(matches) != 1 {
+		return "", false
+	}
+	return matches[0], true
+}
+
+// entitiesNamed returns the sorted keys of entities whose bare or qualified
+// name is name.
+func entitiesNamed(entities map[string]*Entity, name string) []string {
+	var keys []string
+	for key, entity := range entities {
+		if entity.Name == name || entity.QualifiedName() == name {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// entityKey chooses the key for an entity about to be added. Entities whose
+// name is already used by another package are qualified, and so is the
+// entity already holding the bare name, along with relationships pointing
+// at it.
+func (d *Diagram) entityKey(entity *Entity) string {
+	qualified := entity.QualifiedName()
+	key := entity.Name
+
+	var clashes []string
+	for k, e := range d.Entities {
+		if e.Name == entity.Name && e.QualifiedName() != qualified {
+			clashes = append(clashes, k)
+		}
+	}
+	for _, k := range clashes {
+		key = qualified
+		e := d.Entities[k]
+		if k != e.Name {
+			continue
+		}
+		delete(d.Entities, k)
+		d.Entities[e.QualifiedName()] = e
+		for _, rel := range d.Relationships {
+			if rel.From == k {
+				rel.From = e.QualifiedName()
+			}
+			if rel.To == k {
+				rel.To = e.QualifiedName()
+			}
+		}
+	}
+
+	return key
+}
+
+// duplicateKey returns the first free numbered key for another entity with
+// the same key, such as User_2.
+func (d *Diagram) duplicateKey(key string) string {
+	for n := 2; ; n++ {
+		numbered := fmt.Sprintf("%s_%d", key, n)
+		if _, ok := d.Entities[numbered]; !ok {
+			return numbered
+		}
+	}
+}
+
+// validateIdentity reports entities defined more than once and entities
+// keyed under a name other than their own.
+func (d *Diagram) validateIdentity() []ValidationError {
+	var errors []ValidationError
+
+	seen := make(map[string]string, len(d.Entities))
+	for _, key := range d.entityNames() {
+		entity := d.Entities[key]
+		qualified := entity.QualifiedName()
+		if first, ok := seen[qualified]; ok {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("Entity[%s]", key),
+				Message: fmt.Sprintf("duplicate entity '%s' (also Entity[%s])", qualified, first),
+			})
+			continue
+		}
+		seen[qualified] = key
+		if key != entity.Name && key != qualified {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("Entity[%s].Name", key),
+				Message: fmt.Sprintf("entity '%s' is keyed as '%s'", qualified, key),
+			})
+		}
+	}
+
+	return errors
+}
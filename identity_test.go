@@ -0,0 +1,261 @@
+package erd
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/sentinel"
+)
+
+// testAccounts builds a diagram with Account entities from two packages.
+func testAccounts() *Diagram {
+	return NewDiagram("Accounts").
+		AddEntity(NewEntity("Account").
+			WithPackage("billing").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddRelationship(NewRelationship("Invoice", "Account", "Account", ManyToOne)).
+		AddEntity(NewEntity("Invoice").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("AccountID", "string").WithForeignKey())).
+		AddEntity(NewEntity("Account").
+			WithPackage("auth").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("Password", "string")))
+}
+
+func TestEntity_QualifiedName(t *testing.T) {
+	if got := NewEntity("User").QualifiedName(); got != "User" {
+		t.Errorf("QualifiedName() = %q, want %q", got, "User")
+	}
+	if got := NewEntity("User").WithPackage("github.com/app/models").QualifiedName(); got != "github.com/app/models.User" {
+		t.Errorf("QualifiedName() = %q, want %q", got, "github.com/app/models.User")
+	}
+}
+
+func TestDiagram_AddEntity_Collision(t *testing.T) {
+	d := testAccounts()
+
+	keys := make([]string, 0, len(d.Entities))
+	for key := range d.Entities {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if want := []string{"Invoice", "auth.Account", "billing.Account"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("entity keys = %v, want %v", keys, want)
+	}
+
+	// Relationships added before the collision follow the re-keyed entity
+	if rel := d.Relationships[0]; rel.To != "billing.Account" {
+		t.Errorf("relationship target = %q, want %q", rel.To, "billing.Account")
+	}
+
+	if errs := d.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() unexpected errors: %v", errs)
+	}
+}
+
+func TestDiagram_Entity(t *testing.T) {
+	d := testAccounts()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Invoice", "Invoice"},
+		{"billing.Account", "billing.Account"},
+		{"auth.Account", "auth.Account"},
+		{"Account", ""},
+		{"Missing", ""},
+	}
+
+	for _, tt := range tests {
+		got := d.Entity(tt.name)
+		switch {
+		case tt.want == "" && got != nil:
+			t.Errorf("Entity(%q) = %s, want nil", tt.name, got.QualifiedName())
+		case tt.want != "" && (got == nil || got.QualifiedName() != tt.want):
+			t.Errorf("Entity(%q) = %v, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiagram_Validate_Identity(t *testing.T) {
+	tests := []struct {
+		name    string
+		diagram func() *Diagram
+		field   string
+		message string
+	}{
+		{
+			name: "duplicate definition",
+			diagram: func() *Diagram {
+				return NewDiagram("Test").
+					AddEntity(NewEntity("User").AddAttribute(NewAttribute("ID", "string"))).
+					AddEntity(NewEntity("User").AddAttribute(NewAttribute("Email", "string")))
+			},
+			field:   "Entity[User_2]",
+			message: "duplicate entity 'User' (also Entity[User])",
+		},
+		{
+			name: "duplicate under another key",
+			diagram: func() *Diagram {
+				d := NewDiagram("Test").AddEntity(NewEntity("User").WithPackage("models").AddAttribute(NewAttribute("ID", "string")))
+				d.Entities["models.User"] = NewEntity("User").WithPackage("models").AddAttribute(NewAttribute("ID", "string"))
+				return d
+			},
+			field:   "Entity[models.User]",
+			message: "duplicate entity 'models.User' (also Entity[User])",
+		},
+		{
+			name: "mismatched key",
+			diagram: func() *Diagram {
+				d := NewDiagram("Test")
+				d.Entities["Customer"] = NewEntity("User").AddAttribute(NewAttribute("ID", "string"))
+				return d
+			},
+			field:   "Entity[Customer].Name",
+			message: "entity 'User' is keyed as 'Customer'",
+		},
+		{
+			name: "ambiguous reference",
+			diagram: func() *Diagram {
+				return testAccounts().AddRelationship(NewRelationship("Invoice", "Account", "Payer", ManyToOne))
+			},
+			field:   "Relationship[1].To",
+			message: "entity 'Account' is ambiguous: use one of auth.Account, billing.Account",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.diagram().Validate()
+			for _, err := range errs {
+				if err.Field == tt.field && err.Message == tt.message {
+					return
+				}
+			}
+			t.Errorf("Validate() should report %s: %s, got %v", tt.field, tt.message, errs)
+		})
+	}
+}
+
+func TestDiagram_AddEntity_Duplicate(t *testing.T) {
+	first := NewEntity("User").AddAttribute(NewAttribute("ID", "string"))
+	second := NewEntity("User").AddAttribute(NewAttribute("Email", "string"))
+	d := NewDiagram("Test").AddEntity(first).AddEntity(second).AddEntity(NewEntity("User").AddAttribute(NewAttribute("Name", "string")))
+
+	// Neither definition is lost
+	for key, want := range map[string]*Entity{"User": first, "User_2": second} {
+		if d.Entities[key] != want {
+			t.Errorf("Entities[%q] = %v, want %v", key, d.Entities[key], want)
+		}
+	}
+	if _, ok := d.Entities["User_3"]; !ok {
+		t.Error("expected a third definition keyed User_3")
+	}
+
+	// Duplicates are still reported after filtering and a JSON round trip
+	filtered, err := d.Filter(FilterSpec{Include: []string{"User*"}})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := d.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	read, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	for name, diagram := range map[string]*Diagram{"original": d, "filtered": filtered, "json": read} {
+		if errs := diagram.Validate(); len(errs) != 2 {
+			t.Errorf("%s: Validate() = %v, want two duplicates", name, errs)
+		}
+	}
+}
+
+func TestValidateEntityReference(t *testing.T) {
+	entities := testAccounts().Entities
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Invoice", ""},
+		{"billing.Account", ""},
+		{"auth.Account", ""},
+		{"Account", "entity 'Account' is ambiguous: use one of auth.Account, billing.Account"},
+		{"Missing", "entity 'Missing' does not exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateEntityReference(entities, tt.name); got != tt.want {
+				t.Errorf("validateEntityReference(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromSchema_Collision(t *testing.T) {
+	schema := map[string]sentinel.Metadata{
+		"app/billing.Account": {
+			FQDN:        "app/billing.Account",
+			TypeName:    "Account",
+			PackageName: "app/billing",
+			Fields:      []sentinel.FieldMetadata{{Name: "ID", Type: "string", Tags: map[string]string{"erd": "pk"}}},
+		},
+		"app/auth.Account": {
+			FQDN:        "app/auth.Account",
+			TypeName:    "Account",
+			PackageName: "app/auth",
+			Fields:      []sentinel.FieldMetadata{{Name: "ID", Type: "string", Tags: map[string]string{"erd": "pk"}}},
+		},
+		"app/billing.Invoice": {
+			FQDN:        "app/billing.Invoice",
+			TypeName:    "Invoice",
+			PackageName: "app/billing",
+			Fields: []sentinel.FieldMetadata{
+				{Name: "ID", Type: "string", Tags: map[string]string{"erd": "pk"}},
+				{Name: "Account", Type: "*billing.Account"},
+			},
+			Relationships: []sentinel.TypeRelationship{
+				{From: "app/billing.Invoice", To: "app/billing.Account", Field: "Account", Kind: sentinel.RelationshipReference},
+			},
+		},
+	}
+
+	d := FromSchema("Collision", schema)
+
+	if len(d.Entities) != 3 {
+		t.Fatalf("expected 3 entities, got %d", len(d.Entities))
+	}
+	for _, key := range []string{"app/billing.Account", "app/auth.Account", "Invoice"} {
+		if _, ok := d.Entities[key]; !ok {
+			t.Errorf("expected entity keyed %q", key)
+		}
+	}
+	if rel := d.Relationships[0]; rel.From != "Invoice" || rel.To != "app/billing.Account" {
+		t.Errorf("relationship = %s -> %s, want Invoice -> app/billing.Account", rel.From, rel.To)
+	}
+	if errs := d.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() unexpected errors: %v", errs)
+	}
+
+	mermaid := d.ToMermaid()
+	for _, exp := range []string{"    app_billing_Account {\n", "    app_auth_Account {\n", "    Invoice ||--|| app_billing_Account : Account\n"} {
+		if !strings.Contains(mermaid, exp) {
+			t.Errorf("ToMermaid() should contain %q, got:\n%s", exp, mermaid)
+		}
+	}
+
+	dot := d.ToDOT()
+	for _, exp := range []string{`app_billing_Account [label="{app/billing.Account|`, `app_auth_Account [label="{app/auth.Account|`} {
+		if !strings.Contains(dot, exp) {
+			t.Errorf("ToDOT() should contain %q, got:\n%s", exp, dot)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package erd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONVersion is the version of the JSON representation written by
+// [Diagram.WriteJSON]. [ReadJSON] rejects documents with any other version.
+const JSONVersion = 1
+
+// jsonDiagram is the versioned wire representation of a Diagram.
+// Entities are keyed by their name in the diagram, so the document
+// round-trips exactly and is written in a stable order.
+type jsonDiagram struct {
+	Description   *string                `json:"description,omitempty"`
+	Entities      map[string]*jsonEntity `json:"entities"`
+	Title         string                 `json:"title"`
+	Relationships []*jsonRelationship    `json:"relationships"`
+	Version       int                    `json:"version"`
+}
+
+// jsonEntity is the wire representation of an Entity.
+type jsonEntity struct {
+	Package    *string          `json:"package,omitempty"`
+	Note       *string          `json:"note,omitempty"`
+	Name       string           `json:"name"`
+	Attributes []*jsonAttribute `json:"attributes"`
+	Keys       []*jsonKey       `json:"keys,omitempty"`
+}
+
+// jsonKey is the wire representation of a Key.
+type jsonKey struct {
+	Reference  *jsonKeyReference `json:"reference,omitempty"`
+	Name       string            `json:"name"`
+	Type       KeyType           `json:"type"`
+	Attributes []string          `json:"attributes"`
+}
+
+// jsonKeyReference is the wire representation of a KeyReference.
+type jsonKeyReference struct {
+	Entity     string   `json:"entity"`
+	Attributes []string `json:"attributes,omitempty"`
+}
+
+// jsonAttribute is the wire representation of an Attribute.
+type jsonAttribute struct {
+	Key      *KeyType `json:"key,omitempty"`
+	Note     *string  `json:"note,omitempty"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Nullable bool     `json:"nullable,omitempty"`
+}
+
+// jsonRelationship is the wire representation of a Relationship.
+type jsonRelationship struct {
+	Label             *string        `json:"label,omitempty"`
+	Note              *string        `json:"note,omitempty"`
+	FromParticipation *Participation `json:"from_participation,omitempty"`
+	ToParticipation   *Participation `json:"to_participation,omitempty"`
+	From              string         `json:"from"`
+	To                string         `json:"to"`
+	Field             string         `json:"field"`
+	Cardinality       Cardinality    `json:"cardinality"`
+	FromAttributes    []string       `json:"from_attributes,omitempty"`
+	ToAttributes      []string       `json:"to_attributes,omitempty"`
+}
+
+// WriteJSON writes the diagram as an indented, versioned JSON document.
+// The output is deterministic and can be read back with [ReadJSON].
+func (d *Diagram) WriteJSON(w io.Writer) error {
+	doc := &jsonDiagram{
+		Version:       JSONVersion,
+		Title:         d.Title,
+		Description:   d.Description,
+		Entities:      make(map[string]*jsonEntity, len(d.Entities)),
+		Relationships: make([]*jsonRelationship, 0, len(d.Relationships)),
+	}
+
+	for key, entity := range d.Entities {
+		doc.Entities[key] = toJSONEntity(entity)
+	}
+
+	for _, rel := range d.Relationships {
+		doc.Relationships = append(doc.Relationships, &jsonRelationship{
+			Label:             rel.Label,
+			Note:              rel.Note,
+			FromParticipation: rel.FromParticipation,
+			ToParticipation:   rel.ToParticipation,
+			From:              rel.From,
+			To:                rel.To,
+			Field:             rel.Field,
+			Cardinality:       rel.Cardinality,
+			FromAttributes:    rel.FromAttributes,
+			ToAttributes:      rel.ToAttributes,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encoding diagram: %w", err)
+	}
+	return nil
+}
+
+// ReadJSON reads a diagram written by [Diagram.WriteJSON].
+// The result is not validated; call [Diagram.Validate] before rendering
+// diagrams from untrusted sources.
+func ReadJSON(r io.Reader) (*Diagram, error) {
+	var doc jsonDiagram
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding diagram: %w", err)
+	}
+
+	if doc.Version != JSONVersion {
+		return nil, fmt.Errorf("unsupported diagram version %d (want %d)", doc.Version, JSONVersion)
+	}
+
+	diagram := NewDiagram(doc.Title)
+	diagram.Description = doc.Description
+
+	for key, entity := range doc.Entities {
+		if entity == nil {
+			return nil, fmt.Errorf("entity %q is null", key)
+		}
+		e, err := fromJSONEntity(entity)
+		if err != nil {
+			return nil, fmt.Errorf("entity %q: %w", key, err)
+		}
+		diagram.Entities[key] = e
+	}
+
+	for i, rel := range doc.Relationships {
+		if rel == nil {
+			return nil, fmt.Errorf("relationship %d is null", i)
+		}
+		diagram.AddRelationship(&Relationship{
+			Label:             rel.Label,
+			Note:              rel.Note,
+			FromParticipation: rel.FromParticipation,
+			ToParticipation:   rel.ToParticipation,
+			From:              rel.From,
+			To:                rel.To,
+			Field:             rel.Field,
+			Cardinality:       rel.Cardinality,
+			FromAttributes:    rel.FromAttributes,
+			ToAttributes:      rel.ToAttributes,
+		})
+	}
+
+	return diagram, nil
+}
+
+// toJSONEntity converts an entity to its wire representation.
+func toJSONEntity(entity *Entity) *jsonEntity {
+	je := &jsonEntity{
+		Package:    entity.Package,
+		Note:       entity.Note,
+		Name:       entity.Name,
+		Attributes: make([]*jsonAttribute, 0, len(entity.Attributes)),
+	}
+
+	for _, attr := range entity.Attributes {
+		je.Attributes = append(je.Attributes, &jsonAttribute{
+			Key:      attr.Key,
+			Note:     attr.Note,
+			Name:     attr.Name,
+			Type:     attr.Type,
+			Nullable: attr.Nullable,
+		})
+	}
+
+	for _, key := range entity.Keys {
+		jk := &jsonKey{
+			Name:       key.Name,
+			Type:       key.Type,
+			Attributes: key.Attributes,
+		}
+		if key.Reference != nil {
+			jk.Reference = &jsonKeyReference{
+				Entity:     key.Reference.Entity,
+				Attributes: key.Reference.Attributes,
+			}
+		}
+		je.Keys = append(je.Keys, jk)
+	}
+
+	return je
+}
+
+// fromJSONEntity converts a wire entity back to an Entity.
+func fromJSONEntity(je *jsonEntity) (*Entity, error) {
+	entity := NewEntity(je.Name)
+	entity.Package = je.Package
+	entity.Note = je.Note
+
+	for i, attr := range je.Attributes {
+		if attr == nil {
+			return nil, fmt.Errorf("attribute %d is null", i)
+		}
+		entity.AddAttribute(&Attribute{
+			Key:      attr.Key,
+			Note:     attr.Note,
+			Name:     attr.Name,
+			Type:     attr.Type,
+			Nullable: attr.Nullable,
+		})
+	}
+
+	for i, jk := range je.Keys {
+		if jk == nil {
+			return nil, fmt.Errorf("key %d is null", i)
+		}
+		key := NewKey(jk.Name, jk.Type, jk.Attributes...)
+		if jk.Reference != nil {
+			key.WithReference(jk.Reference.Entity, jk.Reference.Attributes...)
+		}
+		entity.AddKey(key)
+	}
+
+	return entity, nil
+}
@@ -0,0 +1,182 @@
+package erd
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testJSONDiagram() *Diagram {
+	return NewDiagram("Shop").
+		WithDescription("Online store").
+		AddEntity(NewEntity("User").
+			WithPackage("models").
+			WithNote("Registered customer").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey().WithNote("UUID")).
+			AddAttribute(NewAttribute("Email", "string").WithUnique()).
+			AddAttribute(NewAttribute("Bio", "*string").WithNullable())).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("ID", "int64").WithPrimaryKey()).
+			AddAttribute(NewAttribute("UserID", "string").WithForeignKey())).
+		AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany).
+			WithLabel("places").
+			WithNote("Historical orders")).
+		AddRelationship(NewRelationship("Order", "User", "User", ManyToOne))
+}
+
+func TestDiagram_WriteJSON_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		diagram *Diagram
+	}{
+		{"full diagram", testJSONDiagram()},
+		{"empty diagram", NewDiagram("")},
+		{"composite keys", testCompositeDiagram()},
+		{"relationship attributes", NewDiagram("Orders").
+			AddEntity(NewEntity("User").AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+			AddEntity(NewEntity("Order").AddAttribute(NewAttribute("UserID", "string"))).
+			AddRelationship(NewRelationship("Order", "User", "UserID", ManyToOne).
+				WithAttributes([]string{"UserID"}, []string{"ID"}))},
+		{"relationship participation", NewDiagram("Profiles").
+			AddEntity(NewEntity("User")).
+			AddEntity(NewEntity("Profile")).
+			AddRelationship(NewRelationship("User", "Profile", "Profile", OneToOne).
+				WithFromParticipation(ExactlyOne).
+				WithToParticipation(ZeroOrOne))},
+		{"entity keyed differently from its name", &Diagram{
+			Title:         "Keys",
+			Entities:      map[string]*Entity{"billing.Account": NewEntity("Account")},
+			Relationships: []*Relationship{},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.diagram.WriteJSON(&buf); err != nil {
+				t.Fatalf("WriteJSON() unexpected error: %v", err)
+			}
+
+			got, err := ReadJSON(&buf)
+			if err != nil {
+				t.Fatalf("ReadJSON() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.diagram) {
+				t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", got, tt.diagram)
+			}
+		})
+	}
+}
+
+func TestDiagram_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testJSONDiagram().WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() unexpected error: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		`"version": 1`,
+		`"title": "Shop"`,
+		`"description": "Online store"`,
+		`"package": "models"`,
+		`"key": "PK"`,
+		`"nullable": true`,
+		`"cardinality": "one-to-many"`,
+		`"label": "places"`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("WriteJSON() output should contain %q, got:\n%s", exp, output)
+		}
+	}
+
+	// Entities are keyed and sorted by name
+	if strings.Index(output, `"Order": {`) > strings.Index(output, `"User": {`) {
+		t.Error("entities should be written in name order")
+	}
+
+	// Unset optional fields are omitted
+	order := output[strings.Index(output, `"Order": {`):strings.Index(output, `"User": {`)]
+	for _, field := range []string{`"package"`, `"note"`, `"nullable"`} {
+		if strings.Contains(order, field) {
+			t.Errorf("Order entity should omit %s, got:\n%s", field, order)
+		}
+	}
+
+	// Output is deterministic
+	var again bytes.Buffer
+	if err := testJSONDiagram().WriteJSON(&again); err != nil {
+		t.Fatalf("WriteJSON() unexpected error: %v", err)
+	}
+	if again.String() != output {
+		t.Error("WriteJSON() output should be deterministic")
+	}
+}
+
+func TestReadJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"malformed", `{"version": 1,`, "decoding diagram"},
+		{"missing version", `{"title": "Test"}`, "unsupported diagram version 0"},
+		{"future version", `{"version": 2, "title": "Test"}`, "unsupported diagram version 2"},
+		{"null entity", `{"version": 1, "entities": {"User": null}}`, `entity "User" is null`},
+		{"null attribute", `{"version": 1, "entities": {"User": {"name": "User", "attributes": [null]}}}`, "attribute 0 is null"},
+		{"null relationship", `{"version": 1, "relationships": [null]}`, "relationship 0 is null"},
+		{"wrong type", `{"version": 1, "title": 42}`, "decoding diagram"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadJSON(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatal("ReadJSON() expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadJSON() error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadJSON_ReadError(t *testing.T) {
+	if _, err := ReadJSON(failingReader{}); err == nil {
+		t.Error("ReadJSON() expected error from reader")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestDiagram_WriteJSON_WriteError(t *testing.T) {
+	if err := testJSONDiagram().WriteJSON(failingWriter{}); err == nil {
+		t.Error("WriteJSON() expected error from writer")
+	}
+}
+
+func TestReadJSON_Validate(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testJSONDiagram().WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() unexpected error: %v", err)
+	}
+
+	diagram, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatalf("ReadJSON() unexpected error: %v", err)
+	}
+	if errs := diagram.Validate(); len(errs) > 0 {
+		t.Errorf("Validate() unexpected errors: %v", errs)
+	}
+	if diagram.ToMermaid() != testJSONDiagram().ToMermaid() {
+		t.Error("diagram read from JSON should render identically")
+	}
+}
@@ -0,0 +1,319 @@
+package erd
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed schema.json
+var jsonSchemaDocument []byte
+
+// JSONSchema returns the JSON Schema (draft 2020-12) describing the document
+// written by [Diagram.WriteJSON]. Point editors at it to validate
+// hand-authored diagram files.
+func JSONSchema() []byte {
+	schema := make([]byte, len(jsonSchemaDocument))
+	copy(schema, jsonSchemaDocument)
+	return schema
+}
+
+// ValidateJSON checks a diagram document against [JSONSchema].
+// Schema violations are returned as validation errors whose fields follow
+// [Diagram.Validate], e.g. "Entity[User].Attribute[0].Type". An error is
+// returned only if the document cannot be read or is not well-formed JSON.
+func ValidateJSON(r io.Reader) ([]ValidationError, error) {
+	schema, err := loadJSONSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding diagram: %w", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("decoding diagram: unexpected data after document")
+	}
+
+	v := &schemaValidator{defs: schema.Defs}
+	v.validate(schema, doc, "")
+	return v.errors, nil
+}
+
+// jsonSchema is the subset of JSON Schema used by schema.json.
+type jsonSchema struct {
+	Const                any                    `json:"const"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Defs                 map[string]*jsonSchema `json:"$defs"`
+	Items                *jsonSchema            `json:"items"`
+	additional           *jsonSchema
+	Ref                  string          `json:"$ref"`
+	Type                 string          `json:"type"`
+	AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	Required             []string        `json:"required"`
+	Enum                 []any           `json:"enum"`
+	MinLength            int             `json:"minLength"`
+	closed               bool
+}
+
+// loadJSONSchema parses the embedded schema once.
+var loadJSONSchema = sync.OnceValues(func() (*jsonSchema, error) {
+	var schema jsonSchema
+	if err := json.Unmarshal(jsonSchemaDocument, &schema); err != nil {
+		return nil, fmt.Errorf("parsing JSON schema: %w", err)
+	}
+	if err := schema.prepare(); err != nil {
+		return nil, fmt.Errorf("parsing JSON schema: %w", err)
+	}
+	return &schema, nil
+})
+
+// prepare resolves additionalProperties, which is either a boolean or a
+// schema, throughout the schema tree.
+func (s *jsonSchema) prepare() error {
+	switch raw := strings.TrimSpace(string(s.AdditionalProperties)); raw {
+	case "", "true":
+	case "false":
+		s.closed = true
+	default:
+		s.additional = &jsonSchema{}
+		if err := json.Unmarshal(s.AdditionalProperties, s.additional); err != nil {
+			return err
+		}
+	}
+
+	children := []*jsonSchema{s.Items, s.additional}
+	for _, child := range s.Properties {
+		children = append(children, child)
+	}
+	for _, child := range s.Defs {
+		children = append(children, child)
+	}
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		if err := child.prepare(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonCollections maps collection fields to the name used for their members
+// in validation error paths.
+var jsonCollections = map[string]string{
+	"Entities":      "Entity",
+	"Attributes":    "Attribute",
+	"Keys":          "Key",
+	"Relationships": "Relationship",
+}
+
+// schemaValidator walks a decoded document alongside a schema, collecting
+// validation errors.
+type schemaValidator struct {
+	defs   map[string]*jsonSchema
+	errors []ValidationError
+}
+
+// validate checks value against schema s, reporting errors at path.
+func (v *schemaValidator) validate(s *jsonSchema, value any, path string) {
+	if s.Ref != "" {
+		ref, ok := v.defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok {
+			v.fail(path, fmt.Sprintf("unresolved schema reference %s", s.Ref))
+			return
+		}
+		s = ref
+	}
+
+	if s.Type != "" && !matchesJSONType(s.Type, value) {
+		v.fail(path, fmt.Sprintf("expected %s, got %s", s.Type, jsonTypeOf(value)))
+		return
+	}
+
+	if s.Const != nil && !equalJSON(s.Const, value) {
+		v.fail(path, fmt.Sprintf("must be %s", formatJSON(s.Const)))
+	}
+
+	if len(s.Enum) > 0 && !containsJSON(s.Enum, value) {
+		options := make([]string, len(s.Enum))
+		for i, option := range s.Enum {
+			options[i] = formatJSON(option)
+		}
+		v.fail(path, fmt.Sprintf("invalid value %s, must be one of %s", formatJSON(value), strings.Join(options, ", ")))
+	}
+
+	switch val := value.(type) {
+	case string:
+		if n := len([]rune(val)); n < s.MinLength {
+			if s.MinLength == 1 {
+				v.fail(path, "must not be empty")
+			} else {
+				v.fail(path, fmt.Sprintf("must be at least %d characters", s.MinLength))
+			}
+		}
+	case map[string]any:
+		v.validateObject(s, val, path)
+	case []any:
+		if s.Items != nil {
+			for i, item := range val {
+				v.validate(s.Items, item, memberPath(path, fmt.Sprint(i)))
+			}
+		}
+	}
+}
+
+// validateObject checks required, declared and additional properties.
+func (v *schemaValidator) validateObject(s *jsonSchema, obj map[string]any, path string) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			v.fail(fieldPath(path, name), "field is required")
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch prop, ok := s.Properties[key]; {
+		case ok:
+			v.validate(prop, obj[key], fieldPath(path, key))
+		case s.additional != nil:
+			v.validate(s.additional, obj[key], memberPath(path, key))
+		case s.closed:
+			v.fail(fieldPath(path, key), "unknown field")
+		}
+	}
+}
+
+// fail records a validation error at path.
+func (v *schemaValidator) fail(path, message string) {
+	if path == "" {
+		path = "Diagram"
+	}
+	v.errors = append(v.errors, ValidationError{Field: path, Message: message})
+}
+
+// fieldPath appends a JSON property to a path, using the Go field name:
+// from_attributes becomes FromAttributes.
+func fieldPath(path, property string) string {
+	var field string
+	for _, part := range strings.Split(property, "_") {
+		if part != "" {
+			field += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// memberPath turns a collection path such as "Entities" into a member path
+// such as "Entity[User]".
+func memberPath(path, key string) string {
+	// Find the last separator outside brackets; keys may contain dots
+	sep, depth := -1, 0
+	for i := len(path) - 1; i >= 0 && sep < 0; i-- {
+		switch path[i] {
+		case ']':
+			depth++
+		case '[':
+			depth--
+		case '.':
+			if depth == 0 {
+				sep = i
+			}
+		}
+	}
+
+	parent, last := path[:sep+1], path[sep+1:]
+	if member, ok := jsonCollections[last]; ok {
+		last = member
+	}
+	return fmt.Sprintf("%s%s[%s]", parent, last, key)
+}
+
+// matchesJSONType reports whether a decoded value has the given schema type.
+func matchesJSONType(typ string, value any) bool {
+	switch typ {
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	default:
+		return jsonTypeOf(value) == typ
+	}
+}
+
+// jsonTypeOf names the JSON type of a decoded value.
+func jsonTypeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// equalJSON compares a schema value with a document value.
+// Numbers compare by value regardless of how they were decoded.
+func equalJSON(a, b any) bool {
+	return formatJSON(a) == formatJSON(b)
+}
+
+// containsJSON reports whether value is one of options.
+func containsJSON(options []any, value any) bool {
+	for _, option := range options {
+		if equalJSON(option, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatJSON renders a decoded scalar for comparison and error messages.
+func formatJSON(value any) string {
+	switch val := value.(type) {
+	case json.Number:
+		if f, err := val.Float64(); err == nil {
+			return fmt.Sprint(f)
+		}
+		return val.String()
+	case string:
+		return fmt.Sprintf("%q", val)
+	case map[string]any, []any:
+		return jsonTypeOf(value)
+	default:
+		return fmt.Sprint(val)
+	}
+}
@@ -0,0 +1,251 @@
+package erd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	schema := JSONSchema()
+
+	var doc map[string]any
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		t.Fatalf("JSONSchema() is not valid JSON: %v", err)
+	}
+	if doc["$schema"] != "https://json-schema.org/draft/2020-12/schema" {
+		t.Errorf("unexpected $schema: %v", doc["$schema"])
+	}
+
+	expected := []string{`"PK", "FK", "UK"`, `"one-to-one", "one-to-many", "many-to-one", "many-to-many"`}
+	for _, exp := range expected {
+		if !strings.Contains(string(schema), exp) {
+			t.Errorf("JSONSchema() should contain %s", exp)
+		}
+	}
+
+	// Callers get their own copy
+	schema[0] = 'x'
+	if JSONSchema()[0] != '{' {
+		t.Error("JSONSchema() should return a copy")
+	}
+}
+
+func TestJSONSchema_Enums(t *testing.T) {
+	var doc struct {
+		Defs map[string]struct {
+			Enum []string `json:"enum"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(JSONSchema(), &doc); err != nil {
+		t.Fatalf("JSONSchema() is not valid JSON: %v", err)
+	}
+
+	for _, key := range doc.Defs["keyType"].Enum {
+		if !isValidKeyType(KeyType(key)) {
+			t.Errorf("schema key type %q is not a valid KeyType", key)
+		}
+	}
+	if len(doc.Defs["keyType"].Enum) != 3 {
+		t.Errorf("schema should list 3 key types, got %v", doc.Defs["keyType"].Enum)
+	}
+
+	for _, c := range doc.Defs["cardinality"].Enum {
+		if !isValidCardinality(Cardinality(c)) {
+			t.Errorf("schema cardinality %q is not a valid Cardinality", c)
+		}
+	}
+	for _, p := range doc.Defs["participation"].Enum {
+		if !isValidParticipation(Participation(p)) {
+			t.Errorf("schema participation %q is not a valid Participation", p)
+		}
+	}
+	if len(doc.Defs["participation"].Enum) != 4 {
+		t.Errorf("schema should list 4 participations, got %v", doc.Defs["participation"].Enum)
+	}
+	if len(doc.Defs["cardinality"].Enum) != 4 {
+		t.Errorf("schema should list 4 cardinalities, got %v", doc.Defs["cardinality"].Enum)
+	}
+}
+
+func TestValidateJSON_WriteJSON(t *testing.T) {
+	for _, d := range []*Diagram{testJSONDiagram(), testCompositeDiagram()} {
+		var buf bytes.Buffer
+		if err := d.WriteJSON(&buf); err != nil {
+			t.Fatalf("WriteJSON() unexpected error: %v", err)
+		}
+
+		errs, err := ValidateJSON(&buf)
+		if err != nil {
+			t.Fatalf("ValidateJSON() unexpected error: %v", err)
+		}
+		if len(errs) > 0 {
+			t.Errorf("WriteJSON() output for %q should satisfy the schema, got %v", d.Title, errs)
+		}
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name: "minimal document",
+			input: `{"version": 1, "title": "Test", "entities": {
+				"User": {"name": "User", "attributes": [{"name": "ID", "type": "string"}]}}}`,
+		},
+		{
+			name:  "not an object",
+			input: `[]`,
+			want:  []string{"Diagram: expected object, got array"},
+		},
+		{
+			name:  "missing required fields",
+			input: `{}`,
+			want: []string{
+				"Version: field is required",
+				"Title: field is required",
+				"Entities: field is required",
+			},
+		},
+		{
+			name:  "wrong version",
+			input: `{"version": 2, "title": "Test", "entities": {}}`,
+			want:  []string{"Version: must be 1"},
+		},
+		{
+			name:  "empty title",
+			input: `{"version": 1, "title": "", "entities": {}}`,
+			want:  []string{"Title: must not be empty"},
+		},
+		{
+			name:  "unknown top-level field",
+			input: `{"version": 1, "title": "Test", "entities": {}, "colour": "red"}`,
+			want:  []string{"Colour: unknown field"},
+		},
+		{
+			name:  "entities wrong type",
+			input: `{"version": 1, "title": "Test", "entities": []}`,
+			want:  []string{"Entities: expected object, got array"},
+		},
+		{
+			name: "attribute type wrong type",
+			input: `{"version": 1, "title": "Test", "entities": {
+				"User": {"name": "User", "attributes": [{"name": "ID", "type": 42}]}}}`,
+			want: []string{"Entity[User].Attribute[0].Type: expected string, got number"},
+		},
+		{
+			name: "attribute missing type",
+			input: `{"version": 1, "title": "Test", "entities": {
+				"User": {"name": "User", "attributes": [{"name": "ID", "type": "string"}, {"name": "Email"}]}}}`,
+			want: []string{"Entity[User].Attribute[1].Type: field is required"},
+		},
+		{
+			name: "invalid key type",
+			input: `{"version": 1, "title": "Test", "entities": {
+				"User": {"name": "User", "attributes": [{"name": "ID", "type": "string", "key": "PRIMARY"}]}}}`,
+			want: []string{`Entity[User].Attribute[0].Key: invalid value "PRIMARY", must be one of "PK", "FK", "UK"`},
+		},
+		{
+			name: "nullable wrong type",
+			input: `{"version": 1, "title": "Test", "entities": {
+				"User": {"name": "User", "attributes": [{"name": "ID", "type": "string", "nullable": "yes"}]}}}`,
+			want: []string{"Entity[User].Attribute[0].Nullable: expected boolean, got string"},
+		},
+		{
+			name: "unknown entity field",
+			input: `{"version": 1, "title": "Test", "entities": {
+				"User": {"name": "User", "attributes": [], "fields": []}}}`,
+			want: []string{"Entity[User].Fields: unknown field"},
+		},
+		{
+			name:  "null entity",
+			input: `{"version": 1, "title": "Test", "entities": {"User": null}}`,
+			want:  []string{"Entity[User]: expected object, got null"},
+		},
+		{
+			name: "invalid relationship",
+			input: `{"version": 1, "title": "Test", "entities": {}, "relationships": [
+				{"from": "User", "to": "Order", "field": "Orders", "cardinality": "one-to-many"},
+				{"from": "User", "to": "", "cardinality": "several"}]}`,
+			want: []string{
+				"Relationship[1].Field: field is required",
+				`Relationship[1].Cardinality: invalid value "several", must be one of "one-to-one", "one-to-many", "many-to-one", "many-to-many"`,
+				"Relationship[1].To: must not be empty",
+			},
+		},
+		{
+			name: "invalid relationship participation and attributes",
+			input: `{"version": 1, "title": "Test", "entities": {}, "relationships": [
+				{"from": "Order", "to": "User", "field": "UserID", "cardinality": "many-to-one",
+				 "to_participation": "2", "from_attributes": [42]}]}`,
+			want: []string{
+				"Relationship[0].FromAttributes[0]: expected string, got number",
+				`Relationship[0].ToParticipation: invalid value "2", must be one of "0..1", "1..1", "0..N", "1..N"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := ValidateJSON(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ValidateJSON() unexpected error: %v", err)
+			}
+
+			got := make([]string, len(errs))
+			for i, e := range errs {
+				got[i] = e.Error()
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ValidateJSON() errors:\ngot  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"malformed", `{"version": 1,`},
+		{"empty", ``},
+		{"trailing data", `{"version": 1} {}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ValidateJSON(strings.NewReader(tt.input)); err == nil {
+				t.Error("ValidateJSON() expected error")
+			}
+		})
+	}
+
+	if _, err := ValidateJSON(failingReader{}); err == nil {
+		t.Error("ValidateJSON() expected error from reader")
+	}
+}
+
+func TestMemberPath(t *testing.T) {
+	tests := []struct {
+		path string
+		key  string
+		want string
+	}{
+		{"Entities", "User", "Entity[User]"},
+		{"Entity[User].Attributes", "0", "Entity[User].Attribute[0]"},
+		{"Relationships", "2", "Relationship[2]"},
+		{"Entity[billing.Account].Attributes", "1", "Entity[billing.Account].Attribute[1]"},
+		{"Tags", "1", "Tags[1]"},
+	}
+
+	for _, tt := range tests {
+		if got := memberPath(tt.path, tt.key); got != tt.want {
+			t.Errorf("memberPath(%q, %q) = %q, want %q", tt.path, tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package erd
+
+import (
+	"slices"
+	"strings"
+)
+
+// keyTypeOrder is the order key types are listed in, such as "PK, FK".
+var keyTypeOrder = []KeyType{PrimaryKey, ForeignKey, UniqueKey}
+
+// attributeKeys returns the key types an attribute takes part in, from its
+// own Key and the entity's key groups, in PK, FK, UK order. Unknown key
+// types follow in the order they are found. The entity may be nil.
+func attributeKeys(entity *Entity, attr *Attribute) []KeyType {
+	var found []KeyType
+	if attr.Key != nil {
+		found = append(found, *attr.Key)
+	}
+	if entity != nil {
+		for _, key := range entity.Keys {
+			if slices.Contains(key.Attributes, attr.Name) && !slices.Contains(found, key.Type) {
+				found = append(found, key.Type)
+			}
+		}
+	}
+	if len(found) < 2 {
+		return found
+	}
+
+	keys := make([]KeyType, 0, len(found))
+	for _, kt := range keyTypeOrder {
+		if slices.Contains(found, kt) {
+			keys = append(keys, kt)
+		}
+	}
+	for _, kt := range found {
+		if !slices.Contains(keyTypeOrder, kt) {
+			keys = append(keys, kt)
+		}
+	}
+	return keys
+}
+
+// hasKey reports whether an attribute takes part in a key of the given type.
+func hasKey(entity *Entity, attr *Attribute, keyType KeyType) bool {
+	return slices.Contains(attributeKeys(entity, attr), keyType)
+}
+
+// keyLabel joins key types for display, such as "PK, FK".
+func keyLabel(keys []KeyType) string {
+	labels := make([]string, len(keys))
+	for i, kt := range keys {
+		labels[i] = string(kt)
+	}
+	return strings.Join(labels, ", ")
+}
+
+// attributeNamed returns the entity's attribute with the given name.
+func attributeNamed(entity *Entity, name string) *Attribute {
+	for _, attr := range entity.Attributes {
+		if attr.Name == name {
+			return attr
+		}
+	}
+	return nil
+}
+
+// primaryKey returns the attributes of an entity that are part of its
+// primary key.
+func primaryKey(entity *Entity) []*Attribute {
+	var keys []*Attribute
+	for _, attr := range entity.Attributes {
+		if hasKey(entity, attr, PrimaryKey) {
+			keys = append(keys, attr)
+		}
+	}
+	return keys
+}
+
+// isPrimaryKey reports whether the attributes are exactly the entity's
+// primary key.
+func isPrimaryKey(entity *Entity, attrs []string) bool {
+	pk := primaryKey(entity)
+	if len(pk) != len(attrs) {
+		return false
+	}
+	for i, attr := range pk {
+		if attr.Name != attrs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// referencedColumn returns the attribute other entities reference when
+// pointing at the given entity: its first primary key attribute, or its
+// first attribute when no primary key is declared.
+func referencedColumn(entity *Entity) *Attribute {
+	if keys := primaryKey(entity); len(keys) > 0 {
+		return keys[0]
+	}
+	if len(entity.Attributes) > 0 {
+		return entity.Attributes[0]
+	}
+	return nil
+}
+
+// foreignKeyFor returns the foreign key attribute on holder that refers to
+// target: the first attribute of a foreign key declared with a reference to
+// target, or else a foreign key attribute named after the target column, such
+// as UserID or user_id for User.ID. It returns nil when neither exists.
+func foreignKeyFor(holder, target *Entity) *Attribute {
+	if key := declaredForeignKey(holder, target.Name); key != nil {
+		if attr := attributeNamed(holder, key.Attributes[0]); attr != nil {
+			return attr
+		}
+	}
+	for _, attr := range holder.Attributes {
+		if hasKey(holder, attr, ForeignKey) && namedAfterKey(attr.Name, target) {
+			return attr
+		}
+	}
+	return nil
+}
+
+// namedAfterKey reports whether an attribute name is exactly the name of
+// target followed by the name of its primary key, <Target><PK> or
+// <target>_<pk>, such as UserID or user_id for User.ID. Targets without a
+// single-attribute primary key are taken to be keyed by ID.
+func namedAfterKey(attrName string, target *Entity) bool {
+	pk := "ID"
+	if keys := primaryKey(target); len(keys) == 1 {
+		pk = keys[0].Name
+	}
+	return normalizeKeyName(attrName) == normalizeKeyName(target.Name+pk)
+}
+
+// entityForKeyName finds the one entity a foreign key attribute is named
+// after, such as User for UserID or user_id, by the rule of namedAfterKey.
+// It returns an empty string when no entity or several match.
+func (d *Diagram) entityForKeyName(attrName string) string {
+	var matches []string
+	for _, name := range d.entityNames() {
+		if namedAfterKey(attrName, d.Entities[name]) {
+			matches = append(matches, name)
+		}
+	}
+	if len(matches) != 1 {
+		return ""
+	}
+	return matches[0]
+}
+
+// declaredForeignKey returns the first foreign key group on holder declared
+// with a reference to target, or nil.
+func declaredForeignKey(holder *Entity, target string) *Key {
+	for _, key := range holder.Keys {
+		if key.Type == ForeignKey && key.Reference != nil && len(key.Attributes) > 0 && refersTo(key.Reference.Entity, target) {
+			return key
+		}
+	}
+	return nil
+}
+
+// referencedColumns returns the attribute names of target a foreign key
+// refers to: the attributes named by its reference, or else the primary key
+// of target, or its first attribute.
+func referencedColumns(target *Entity, key *Key) []string {
+	if len(key.Reference.Attributes) > 0 {
+		return key.Reference.Attributes
+	}
+	var cols []string
+	for _, attr := range primaryKey(target) {
+		cols = append(cols, attr.Name)
+	}
+	if len(cols) == 0 {
+		if ref := referencedColumn(target); ref != nil {
+			cols = []string{ref.Name}
+		}
+	}
+	return cols
+}
+
+// refersTo reports whether a key reference, given as an entity key or a bare
+// or qualified name, names the entity called name.
+func refersTo(reference, name string) bool {
+	return reference == name || strings.HasSuffix(reference, "."+name)
+}
+
+// normalizeKeyName lowercases a name and strips separators so that
+// UserID, user_id and userId compare equal.
+func normalizeKeyName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "_", "")
+	name = strings.ReplaceAll(name, "-", "")
+	return name
+}
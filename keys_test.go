@@ -0,0 +1,213 @@
+package erd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReferencedColumn(t *testing.T) {
+	tests := []struct {
+		name   string
+		entity *Entity
+		want   string
+	}{
+		{
+			name: "primary key",
+			entity: NewEntity("User").
+				AddAttribute(NewAttribute("Name", "string")).
+				AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()),
+			want: "ID",
+		},
+		{
+			name:   "first attribute fallback",
+			entity: NewEntity("User").AddAttribute(NewAttribute("Name", "string")),
+			want:   "Name",
+		},
+		{
+			name:   "no attributes",
+			entity: NewEntity("User"),
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if attr := referencedColumn(tt.entity); attr != nil {
+				got = attr.Name
+			}
+			if got != tt.want {
+				t.Errorf("referencedColumn() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForeignKeyFor(t *testing.T) {
+	user := NewEntity("User").AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())
+
+	tests := []struct {
+		entity *Entity
+		target *Entity
+		name   string
+		want   string
+	}{
+		{
+			name: "matches by name",
+			entity: NewEntity("Order").
+				AddAttribute(NewAttribute("ShopID", "string").WithForeignKey()).
+				AddAttribute(NewAttribute("user_id", "string").WithForeignKey()),
+			target: user,
+			want:   "user_id",
+		},
+		{
+			name: "matches the target primary key name",
+			entity: NewEntity("Order").
+				AddAttribute(NewAttribute("UserHandle", "string").WithForeignKey()),
+			target: NewEntity("User").AddAttribute(NewAttribute("Handle", "string").WithPrimaryKey()),
+			want:   "UserHandle",
+		},
+		{
+			name: "declared reference",
+			entity: NewEntity("Order").
+				AddAttribute(NewAttribute("Buyer", "string")).
+				AddKey(NewKey("fk_buyer", ForeignKey, "Buyer").WithReference("User")),
+			target: user,
+			want:   "Buyer",
+		},
+		{
+			name: "lone foreign key to another entity",
+			entity: NewEntity("Order").
+				AddAttribute(NewAttribute("ShopID", "string").WithForeignKey()),
+			target: user,
+			want:   "",
+		},
+		{
+			name: "longer name with the target as prefix",
+			entity: NewEntity("Order").
+				AddAttribute(NewAttribute("UserGroupID", "string").WithForeignKey()),
+			target: user,
+			want:   "",
+		},
+		{
+			name:   "untagged attributes are ignored",
+			entity: NewEntity("Order").AddAttribute(NewAttribute("UserID", "string")),
+			target: user,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if attr := foreignKeyFor(tt.entity, tt.target); attr != nil {
+				got = attr.Name
+			}
+			if got != tt.want {
+				t.Errorf("foreignKeyFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// testCompositeDiagram has an order keyed by tenant and line items with a
+// composite foreign key to it.
+func testCompositeDiagram() *Diagram {
+	return NewDiagram("Orders").
+		AddEntity(NewEntity("Tenant").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("TenantID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("Number", "string")).
+			AddKey(NewKey("fk_tenant", ForeignKey, "TenantID").WithReference("Tenant", "ID")).
+			AddKey(NewKey("uk_number", UniqueKey, "TenantID", "Number"))).
+		AddEntity(NewEntity("LineItem").
+			AddAttribute(NewAttribute("TenantID", "string")).
+			AddAttribute(NewAttribute("OrderID", "string")).
+			AddAttribute(NewAttribute("SKU", "string")).
+			AddKey(NewKey("pk", PrimaryKey, "TenantID", "OrderID", "SKU")).
+			AddKey(NewKey("fk_order", ForeignKey, "TenantID", "OrderID").WithReference("Order", "TenantID", "ID"))).
+		AddRelationship(NewRelationship("Order", "LineItem", "Items", OneToMany))
+}
+
+func TestAttributeKeys(t *testing.T) {
+	d := testCompositeDiagram()
+
+	tests := []struct {
+		entity string
+		attr   string
+		want   string
+	}{
+		{"Order", "TenantID", "PK, FK, UK"},
+		{"Order", "ID", "PK"},
+		{"Order", "Number", "UK"},
+		{"LineItem", "OrderID", "PK, FK"},
+		{"LineItem", "SKU", "PK"},
+		{"Tenant", "ID", "PK"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entity+"."+tt.attr, func(t *testing.T) {
+			entity := d.Entities[tt.entity]
+			if got := keyLabel(attributeKeys(entity, attributeNamed(entity, tt.attr))); got != tt.want {
+				t.Errorf("attributeKeys() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := attributeKeys(nil, NewAttribute("ID", "string").WithUnique()); len(got) != 1 || got[0] != UniqueKey {
+		t.Errorf("attributeKeys(nil) = %v, want [UK]", got)
+	}
+}
+
+func TestPrimaryKey_Composite(t *testing.T) {
+	d := testCompositeDiagram()
+
+	var names []string
+	for _, attr := range primaryKey(d.Entities["LineItem"]) {
+		names = append(names, attr.Name)
+	}
+	if strings.Join(names, ",") != "TenantID,OrderID,SKU" {
+		t.Errorf("primaryKey() = %v, want [TenantID OrderID SKU]", names)
+	}
+
+	if attr := foreignKeyFor(d.Entities["LineItem"], d.Entities["Order"]); attr == nil || attr.Name != "TenantID" {
+		t.Errorf("foreignKeyFor() = %v, want the first attribute of the declared key", attr)
+	}
+}
+
+func TestDiagram_EntityForKeyName(t *testing.T) {
+	d := NewDiagram("Shop").
+		AddEntity(NewEntity("User").AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("UserGroup").AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("OrderLine").AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Shop").AddAttribute(NewAttribute("Code", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Account").WithPackage("billing").AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Account").WithPackage("auth").AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()))
+
+	tests := []struct {
+		attr string
+		want string
+	}{
+		{"UserID", "User"},
+		{"user_id", "User"},
+		{"USER_ID", "User"},
+		{"UserId", "User"},
+		{"UserGroupID", "UserGroup"},
+		{"order_line_id", "OrderLine"},
+		{"ShopCode", "Shop"},
+		{"ShopID", ""},
+		{"AccountID", ""},
+		{"UserName", ""},
+		{"ID", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.attr, func(t *testing.T) {
+			if got := d.entityForKeyName(tt.attr); got != tt.want {
+				t.Errorf("entityForKeyName(%q) = %q, want %q", tt.attr, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package erd
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Conflict describes an entity or relationship that two merged diagrams
+// define differently.
+//
+// Entity and Attribute locate attribute conflicts and Entity and Key key
+// group conflicts; Entity, To and Field identify relationship conflicts.
+// Property names what differs ("type", "key", "nullable", "definition",
+// "cardinality", "participation" or "attributes"). Existing holds the value
+// kept in the merged diagram and Incoming the value it conflicts with, taken
+// from diagrams[Diagram]. A key that is not set is represented by an empty
+// string.
+type Conflict struct {
+	Entity    string `json:"entity"`
+	Attribute string `json:"attribute,omitempty"`
+	Key       string `json:"key,omitempty"`
+	To        string `json:"to,omitempty"`
+	Field     string `json:"field,omitempty"`
+	Property  string `json:"property"`
+	Existing  string `json:"existing"`
+	Incoming  string `json:"incoming"`
+	Diagram   int    `json:"diagram"`
+}
+
+// String describes the conflict in a single line.
+func (c Conflict) String() string {
+	var subject string
+	switch {
+	case c.Attribute != "":
+		subject = c.Entity + "." + c.Attribute
+	case c.Key != "":
+		subject = c.Entity + " key " + c.Key
+	default:
+		subject = fmt.Sprintf("%s→%s (%s)", c.Entity, c.To, c.Field)
+	}
+	return fmt.Sprintf("%s: %s %s conflicts with %s in diagram %d",
+		subject, c.Property, conflictValue(c.Existing), conflictValue(c.Incoming), c.Diagram)
+}
+
+// conflictValue returns the value, or "none" for an unset key.
+func conflictValue(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
+
+// Merge combines diagrams into a single diagram with the given title.
+//
+// Entities are matched by qualified name and relationships by source,
+// target and field. The first definition of an entity wins: attributes that
+// later diagrams define with a different type, key or nullability are
+// reported as conflicts rather than overwritten, and attributes only some
+// diagrams define are appended. Key groups are matched by name the same way:
+// new ones are appended and differing definitions reported as conflicts.
+// Identical relationships are kept once; a relationship redefined with a
+// different cardinality, participation or joined attributes is reported as a
+// conflict, and joined attributes only some diagrams record are filled in.
+// The input diagrams are not modified.
+func Merge(title string, diagrams ...*Diagram) (*Diagram, []Conflict) {
+	merged := NewDiagram(title)
+	var conflicts []Conflict
+
+	// Add every entity first so names shared across packages are qualified
+	// before relationships refer to them
+	byQualified := make(map[string]*Entity)
+	for i, d := range diagrams {
+		for _, name := range d.entityNames() {
+			entity := d.Entities[name]
+			existing, ok := byQualified[entity.QualifiedName()]
+			if !ok {
+				clone := cloneEntity(entity)
+				byQualified[entity.QualifiedName()] = clone
+				merged.AddEntity(clone)
+				continue
+			}
+			conflicts = append(conflicts, mergeEntity(existing, entity, i)...)
+		}
+	}
+
+	keys := make(map[*Entity]string, len(merged.Entities))
+	for key, entity := range merged.Entities {
+		keys[entity] = key
+	}
+
+	relationships := make(map[string]*Relationship)
+	for i, d := range diagrams {
+		resolve := func(name string) string {
+			if entity, ok := d.Entities[name]; ok {
+				return keys[byQualified[entity.QualifiedName()]]
+			}
+			return name
+		}
+
+		for _, rel := range d.Relationships {
+			clone := *rel
+			clone.From, clone.To = resolve(rel.From), resolve(rel.To)
+			clone.FromAttributes, clone.ToAttributes = slices.Clone(rel.FromAttributes), slices.Clone(rel.ToAttributes)
+
+			key := clone.From + "\x00" + clone.To + "\x00" + clone.Field
+			existing, ok := relationships[key]
+			if !ok {
+				relationships[key] = &clone
+				merged.Relationships = append(merged.Relationships, &clone)
+				continue
+			}
+			if existing.Cardinality != rel.Cardinality {
+				conflicts = append(conflicts, Conflict{
+					Entity:   clone.From,
+					To:       clone.To,
+					Field:    clone.Field,
+					Property: "cardinality",
+					Existing: string(existing.Cardinality),
+					Incoming: string(rel.Cardinality),
+					Diagram:  i,
+				})
+			} else if old, incoming := participationString(existing), participationString(rel); old != incoming {
+				conflicts = append(conflicts, Conflict{
+					Entity:   clone.From,
+					To:       clone.To,
+					Field:    clone.Field,
+					Property: "participation",
+					Existing: old,
+					Incoming: incoming,
+					Diagram:  i,
+				})
+			}
+
+			// Joined attributes only some diagrams record are filled in
+			switch old, incoming := joinedString(existing), joinedString(rel); {
+			case old == "":
+				existing.FromAttributes, existing.ToAttributes = clone.FromAttributes, clone.ToAttributes
+			case incoming != "" && old != incoming:
+				conflicts = append(conflicts, Conflict{
+					Entity:   clone.From,
+					To:       clone.To,
+					Field:    clone.Field,
+					Property: "attributes",
+					Existing: old,
+					Incoming: incoming,
+					Diagram:  i,
+				})
+			}
+		}
+	}
+
+	return merged, conflicts
+}
+
+// mergeEntity folds a later definition of an entity into the merged one.
+// Conflicts name the entity by its qualified name.
+func mergeEntity(existing, entity *Entity, diagram int) []Conflict {
+	var conflicts []Conflict
+	conflict := func(attr, property, old, incoming string) {
+		conflicts = append(conflicts, Conflict{
+			Entity:    existing.QualifiedName(),
+			Attribute: attr,
+			Property:  property,
+			Existing:  old,
+			Incoming:  incoming,
+			Diagram:   diagram,
+		})
+	}
+
+	if existing.Note == nil {
+		existing.Note = entity.Note
+	}
+
+	attrs := make(map[string]*Attribute, len(existing.Attributes))
+	for _, attr := range existing.Attributes {
+		attrs[attr.Name] = attr
+	}
+
+	for _, key := range entity.Keys {
+		i := slices.IndexFunc(existing.Keys, func(k *Key) bool { return k.Name == key.Name })
+		if i < 0 {
+			existing.Keys = append(existing.Keys, cloneKey(key))
+			continue
+		}
+		if old, incoming := keyDescription(existing.Keys[i], nil), keyDescription(key, nil); old != incoming {
+			conflicts = append(conflicts, Conflict{
+				Entity:   existing.QualifiedName(),
+				Key:      key.Name,
+				Property: "definition",
+				Existing: old,
+				Incoming: incoming,
+				Diagram:  diagram,
+			})
+		}
+	}
+
+	for _, attr := range entity.Attributes {
+		old, ok := attrs[attr.Name]
+		if !ok {
+			clone := *attr
+			existing.Attributes = append(existing.Attributes, &clone)
+			attrs[attr.Name] = &clone
+			continue
+		}
+		if old.Type != attr.Type {
+			conflict(attr.Name, "type", old.Type, attr.Type)
+		}
+		if keyString(old.Key) != keyString(attr.Key) {
+			conflict(attr.Name, "key", keyString(old.Key), keyString(attr.Key))
+		}
+		if old.Nullable != attr.Nullable {
+			conflict(attr.Name, "nullable", nullability(old.Nullable), nullability(attr.Nullable))
+		}
+	}
+
+	return conflicts
+}
+
+// cloneEntity copies an entity, its attributes and its keys.
+func cloneEntity(entity *Entity) *Entity {
+	clone := *entity
+	clone.Attributes = make([]*Attribute, len(entity.Attributes))
+	for i, attr := range entity.Attributes {
+		a := *attr
+		clone.Attributes[i] = &a
+	}
+	clone.Keys = nil
+	for _, key := range entity.Keys {
+		clone.Keys = append(clone.Keys, cloneKey(key))
+	}
+	return &clone
+}
+
+// cloneKey copies a key and its reference.
+func cloneKey(key *Key) *Key {
+	clone := NewKey(key.Name, key.Type, slices.Clone(key.Attributes)...)
+	if key.Reference != nil {
+		clone.WithReference(key.Reference.Entity, slices.Clone(key.Reference.Attributes)...)
+	}
+	return clone
+}
@@ -0,0 +1,230 @@
+package erd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testMergeUsers() *Diagram {
+	return NewDiagram("Users").
+		AddEntity(NewEntity("User").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("Email", "string").WithUnique())).
+		AddEntity(NewEntity("Session").
+			AddAttribute(NewAttribute("Token", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("UserID", "string").WithForeignKey())).
+		AddRelationship(NewRelationship("User", "Session", "Sessions", OneToMany))
+}
+
+func testMergeOrders() *Diagram {
+	return NewDiagram("Orders").
+		AddEntity(NewEntity("User").
+			WithNote("Order owner").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("Name", "string"))).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("UserID", "string").WithForeignKey())).
+		AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany))
+}
+
+func TestMerge(t *testing.T) {
+	users := testMergeUsers()
+	orders := testMergeOrders()
+	// The same relationship reported by both services is kept once
+	orders.AddRelationship(NewRelationship("User", "Session", "Sessions", OneToMany))
+
+	merged, conflicts := Merge("System", users, orders)
+	if len(conflicts) != 0 {
+		t.Fatalf("Merge() unexpected conflicts: %v", conflicts)
+	}
+
+	if merged.Title != "System" {
+		t.Errorf("Title = %q, want %q", merged.Title, "System")
+	}
+	if len(merged.Entities) != 3 {
+		t.Errorf("expected 3 entities, got %d", len(merged.Entities))
+	}
+	if len(merged.Relationships) != 2 {
+		t.Errorf("expected 2 relationships, got %d", len(merged.Relationships))
+	}
+
+	user := merged.Entities["User"]
+	var names []string
+	for _, attr := range user.Attributes {
+		names = append(names, attr.Name)
+	}
+	if want := []string{"ID", "Email", "Name"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("User attributes = %v, want %v", names, want)
+	}
+	if user.Note == nil || *user.Note != "Order owner" {
+		t.Errorf("User note = %v, want %q", user.Note, "Order owner")
+	}
+
+	if errs := merged.Validate(); len(errs) != 0 {
+		t.Errorf("merged diagram should be valid, got %v", errs)
+	}
+
+	// Inputs are left untouched
+	if len(users.Entities["User"].Attributes) != 2 || users.Entities["User"].Note != nil {
+		t.Error("Merge() modified its input")
+	}
+}
+
+func TestMerge_Keys(t *testing.T) {
+	composite := testCompositeDiagram()
+	other := testCompositeDiagram()
+	other.Entities["Order"].AddKey(NewKey("uk_reference", UniqueKey, "Number"))
+
+	merged, conflicts := Merge("Orders", composite, other)
+	if len(conflicts) != 0 {
+		t.Fatalf("Merge() unexpected conflicts: %v", conflicts)
+	}
+	if got := keyNames(merged.Entities["Order"]); !reflect.DeepEqual(got, []string{"fk_tenant", "uk_number", "uk_reference"}) {
+		t.Errorf("Order keys = %v, want [fk_tenant uk_number uk_reference]", got)
+	}
+
+	// Keys are copied, not shared with the inputs
+	merged.Entities["LineItem"].Keys[0].Attributes[0] = "Changed"
+	if composite.Entities["LineItem"].Keys[0].Attributes[0] != "TenantID" {
+		t.Error("Merge() shared keys with its input")
+	}
+}
+
+func TestMerge_KeyConflicts(t *testing.T) {
+	other := testCompositeDiagram()
+	other.Entities["Order"].Keys[1].Attributes = []string{"Number"}
+	other.Entities["LineItem"].Keys[1].Reference.Attributes = nil
+
+	merged, conflicts := Merge("Orders", testCompositeDiagram(), other)
+
+	want := []Conflict{
+		{Entity: "LineItem", Key: "fk_order", Property: "definition", Existing: "FK (TenantID, OrderID) → Order (TenantID, ID)", Incoming: "FK (TenantID, OrderID) → Order", Diagram: 1},
+		{Entity: "Order", Key: "uk_number", Property: "definition", Existing: "UK (TenantID, Number)", Incoming: "UK (Number)", Diagram: 1},
+	}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Fatalf("conflicts =\n%v\nwant\n%v", conflicts, want)
+	}
+
+	// The first definition wins and is not duplicated
+	if got := keyNames(merged.Entities["Order"]); !reflect.DeepEqual(got, []string{"fk_tenant", "uk_number"}) {
+		t.Errorf("Order keys = %v, want [fk_tenant uk_number]", got)
+	}
+	if got := merged.Entities["Order"].Keys[1].Attributes; !reflect.DeepEqual(got, []string{"TenantID", "Number"}) {
+		t.Errorf("uk_number attributes = %v, want [TenantID Number]", got)
+	}
+}
+
+func TestMerge_Conflicts(t *testing.T) {
+	orders := testMergeOrders()
+	user := orders.Entities["User"]
+	user.AddAttribute(NewAttribute("Email", "*string").WithNullable())
+	user.Attributes[0].Key = nil
+	orders.AddRelationship(NewRelationship("User", "Session", "Sessions", OneToOne))
+
+	merged, conflicts := Merge("System", testMergeUsers(), orders)
+
+	want := []Conflict{
+		{Entity: "User", Attribute: "ID", Property: "key", Existing: "PK", Incoming: "", Diagram: 1},
+		{Entity: "User", Attribute: "Email", Property: "type", Existing: "string", Incoming: "*string", Diagram: 1},
+		{Entity: "User", Attribute: "Email", Property: "key", Existing: "UK", Incoming: "", Diagram: 1},
+		{Entity: "User", Attribute: "Email", Property: "nullable", Existing: "not null", Incoming: "nullable", Diagram: 1},
+		{Entity: "User", To: "Session", Field: "Sessions", Property: "cardinality", Existing: "one-to-many", Incoming: "one-to-one", Diagram: 1},
+	}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Fatalf("conflicts =\n%v\nwant\n%v", conflicts, want)
+	}
+
+	// The first definition wins
+	email := findAttribute(merged.Entities["User"], "Email")
+	if email.Type != "string" || email.Nullable || email.Key == nil || *email.Key != UniqueKey {
+		t.Errorf("Email should keep its first definition, got %+v", email)
+	}
+	for _, rel := range merged.Relationships {
+		if rel.Field == "Sessions" && rel.Cardinality != OneToMany {
+			t.Errorf("Sessions should keep its first cardinality, got %s", rel.Cardinality)
+		}
+	}
+}
+
+func TestMerge_ParticipationConflict(t *testing.T) {
+	orders := testMergeOrders()
+	orders.Relationships[0].WithToParticipation(OneOrMore)
+
+	merged, conflicts := Merge("System", testMergeOrders(), orders)
+
+	want := []Conflict{
+		{Entity: "User", To: "Order", Field: "Orders", Property: "participation", Existing: "1..1, 0..N", Incoming: "1..1, 1..N", Diagram: 1},
+	}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Fatalf("conflicts =\n%v\nwant\n%v", conflicts, want)
+	}
+	if len(merged.Relationships) != 1 || merged.Relationships[0].ToParticipation != nil {
+		t.Errorf("Orders should keep its first participation, got %+v", merged.Relationships)
+	}
+}
+
+func TestMerge_JoinedAttributes(t *testing.T) {
+	recorded := testMergeOrders()
+	recorded.Relationships[0].WithAttributes([]string{"ID"}, []string{"UserID"})
+
+	// Attributes only a later diagram records are filled in
+	merged, conflicts := Merge("System", testMergeOrders(), recorded)
+	if len(conflicts) != 0 {
+		t.Fatalf("Merge() unexpected conflicts: %v", conflicts)
+	}
+	if got := joinedAttributes(merged); !reflect.DeepEqual(got, []string{"User[ID] -> Order[UserID] one-to-many"}) {
+		t.Errorf("relationships = %q", got)
+	}
+	merged.Relationships[0].ToAttributes[0] = "Changed"
+	if recorded.Relationships[0].ToAttributes[0] != "UserID" {
+		t.Error("Merge() shared joined attributes with its input")
+	}
+
+	other := testMergeOrders()
+	other.Relationships[0].WithAttributes([]string{"ID"}, []string{"OwnerID"})
+	_, conflicts = Merge("System", recorded, other)
+	want := []Conflict{
+		{Entity: "User", To: "Order", Field: "Orders", Property: "attributes", Existing: "ID → UserID", Incoming: "ID → OwnerID", Diagram: 1},
+	}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Errorf("conflicts =\n%v\nwant\n%v", conflicts, want)
+	}
+}
+
+func TestConflict_String(t *testing.T) {
+	tests := []struct {
+		conflict Conflict
+		want     string
+	}{
+		{
+			Conflict{Entity: "User", Attribute: "Email", Property: "type", Existing: "string", Incoming: "*string", Diagram: 2},
+			"User.Email: type string conflicts with *string in diagram 2",
+		},
+		{
+			Conflict{Entity: "User", Attribute: "ID", Property: "key", Existing: "PK", Diagram: 1},
+			"User.ID: key PK conflicts with none in diagram 1",
+		},
+		{
+			Conflict{Entity: "Order", Key: "uk_number", Property: "definition", Existing: "UK (TenantID, Number)", Incoming: "UK (Number)", Diagram: 1},
+			"Order key uk_number: definition UK (TenantID, Number) conflicts with UK (Number) in diagram 1",
+		},
+		{
+			Conflict{Entity: "User", To: "Order", Field: "Orders", Property: "cardinality", Existing: "one-to-many", Incoming: "one-to-one", Diagram: 1},
+			"User→Order (Orders): cardinality one-to-many conflicts with one-to-one in diagram 1",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.conflict.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestMerge_Empty(t *testing.T) {
+	merged, conflicts := Merge("Empty")
+	if len(merged.Entities) != 0 || len(merged.Relationships) != 0 || len(conflicts) != 0 {
+		t.Errorf("Merge() with no diagrams should be empty, got %+v, %v", merged, conflicts)
+	}
+}
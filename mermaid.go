@@ -2,53 +2,87 @@ package erd
 
 import (
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 )
 
 // ToMermaid generates a Mermaid ERD diagram from the diagram structure.
 func (d *Diagram) ToMermaid() string {
+	return d.ToMermaidWith(RenderOptions{})
+}
+
+// ToMermaidWith generates a Mermaid ERD diagram using the given options.
+func (d *Diagram) ToMermaidWith(opts RenderOptions) string {
 	var sb strings.Builder
+	_ = d.WriteMermaidWith(&sb, opts) // strings.Builder never fails
+	return sb.String()
+}
+
+// WriteMermaid streams a Mermaid ERD diagram to w.
+func (d *Diagram) WriteMermaid(w io.Writer) error {
+	return d.WriteMermaidWith(w, RenderOptions{})
+}
 
-	sb.WriteString("erDiagram\n")
+// WriteMermaidWith streams a Mermaid ERD diagram to w using the given
+// options. Entities and relationships are written one at a time, so memory
+// use does not grow with the size of the output. It returns the first write
+// error, after which nothing more is written.
+func (d *Diagram) WriteMermaidWith(w io.Writer, opts RenderOptions) error {
+	rw := &renderWriter{w: w}
+
+	rw.WriteString("erDiagram\n")
 
 	// Write entities with their attributes (sorted by name for deterministic output)
-	entityNames := make([]string, 0, len(d.Entities))
-	for name := range d.Entities {
-		entityNames = append(entityNames, name)
+	ungrouped, groups := d.entityGroups(opts)
+	for _, name := range ungrouped {
+		writeMermaidEntity(rw, name, d.Entities[name], opts)
 	}
-	sort.Strings(entityNames)
-
-	for _, name := range entityNames {
-		entity := d.Entities[name]
-		sb.WriteString(fmt.Sprintf("    %s {\n", sanitizeName(entity.Name)))
-		for _, attr := range entity.Attributes {
-			sb.WriteString(formatMermaidAttribute(attr))
+	for _, group := range groups {
+		rw.WriteString(fmt.Sprintf("    %%%% package %s\n", group.name))
+		for _, name := range group.keys {
+			writeMermaidEntity(rw, name, d.Entities[name], opts)
 		}
-		sb.WriteString("    }\n")
 	}
 
 	// Write relationships
 	for _, rel := range d.Relationships {
-		sb.WriteString(formatMermaidRelationship(rel))
+		rw.WriteString(formatMermaidRelationship(rel))
 	}
 
-	return sb.String()
+	return rw.err
 }
 
-// formatMermaidAttribute formats an attribute for Mermaid syntax.
-func formatMermaidAttribute(attr *Attribute) string {
+// writeMermaidEntity writes an entity block identified by its key, one
+// attribute at a time. Entities drawn without attributes are declared by
+// name alone.
+func writeMermaidEntity(rw *renderWriter, name string, entity *Entity, opts RenderOptions) {
+	if opts.HideAttributes {
+		rw.WriteString(fmt.Sprintf("    %s\n", sanitizeName(name)))
+		return
+	}
+
+	rw.WriteString(fmt.Sprintf("    %s {\n", sanitizeName(name)))
+	for _, attr := range opts.attributes(entity) {
+		rw.WriteString(formatMermaidAttribute(attr, attributeKeys(entity, attr), opts))
+	}
+	rw.WriteString("    }\n")
+}
+
+// formatMermaidAttribute formats an attribute and its keys for Mermaid
+// syntax.
+func formatMermaidAttribute(attr *Attribute, keys []KeyType, opts RenderOptions) string {
 	var parts []string
 
 	// Type comes first in Mermaid
-	parts = append(parts, sanitizeType(attr.Type))
+	parts = append(parts, mermaidType(attr.Type, opts))
 
 	// Then the name
 	parts = append(parts, attr.Name)
 
-	// Add key constraint if present
-	if attr.Key != nil {
-		parts = append(parts, string(*attr.Key))
+	// Add key constraints if present, such as "PK, FK"
+	if len(keys) > 0 {
+		parts = append(parts, keyLabel(keys))
 	}
 
 	// Add comment for nullable or notes
@@ -56,7 +90,7 @@ func formatMermaidAttribute(attr *Attribute) string {
 	if attr.Nullable {
 		comments = append(comments, "nullable")
 	}
-	if attr.Note != nil {
+	if attr.Note != nil && !opts.HideNotes {
 		comments = append(comments, *attr.Note)
 	}
 
@@ -68,9 +102,21 @@ func formatMermaidAttribute(attr *Attribute) string {
 	return line + "\n"
 }
 
+// mermaidType returns an attribute type Mermaid can parse. Full types keep
+// their package qualifiers with dots replaced.
+func mermaidType(attrType string, opts RenderOptions) string {
+	if opts.FullTypes {
+		attrType = strings.ReplaceAll(attrType, ".", "_")
+	}
+	return sanitizeType(attrType)
+}
+
 // formatMermaidRelationship formats a relationship for Mermaid syntax.
 func formatMermaidRelationship(rel *Relationship) string {
 	symbol := getMermaidCardinality(rel.Cardinality)
+	if rel.hasParticipation() {
+		symbol = crowsFoot(rel.Participation())
+	}
 	label := rel.Field
 	if rel.Label != nil {
 		label = *rel.Label
@@ -99,11 +145,23 @@ func getMermaidCardinality(c Cardinality) string {
 	}
 }
 
+// entityNames returns the diagram's entity keys in sorted order so that
+// every renderer emits entities deterministically.
+func (d *Diagram) entityNames() []string {
+	names := make([]string, 0, len(d.Entities))
+	for name := range d.Entities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // sanitizeName ensures names are valid for Mermaid syntax.
 func sanitizeName(name string) string {
 	name = strings.ReplaceAll(name, " ", "_")
 	name = strings.ReplaceAll(name, "-", "_")
 	name = strings.ReplaceAll(name, ".", "_")
+	name = strings.ReplaceAll(name, "/", "_")
 	return name
 }
 
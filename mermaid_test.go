@@ -1,6 +1,7 @@
 package erd
 
 import (
+	"io"
 	"strings"
 	"testing"
 )
@@ -37,6 +38,28 @@ func TestFormatMermaidRelationship_WithLabel(t *testing.T) {
 	}
 }
 
+func TestFormatMermaidRelationship_Participation(t *testing.T) {
+	tests := []struct {
+		name string
+		rel  *Relationship
+		want string
+	}{
+		{"optional", NewRelationship("User", "Profile", "Profile", OneToOne).WithToParticipation(ZeroOrOne), "    User ||--o| Profile : Profile\n"},
+		{"required", NewRelationship("Company", "Address", "Address", OneToOne).WithToParticipation(ExactlyOne), "    Company ||--|| Address : Address\n"},
+		{"optional many", NewRelationship("User", "Order", "Orders", OneToMany), "    User ||--o{ Order : Orders\n"},
+		{"required many", NewRelationship("Order", "LineItem", "Items", OneToMany).WithToParticipation(OneOrMore), "    Order ||--|{ LineItem : Items\n"},
+		{"optional from", NewRelationship("Order", "Coupon", "Coupon", ManyToOne).WithFromParticipation(OneOrMore).WithToParticipation(ZeroOrOne), "    Order }|--o| Coupon : Coupon\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatMermaidRelationship(tt.rel); got != tt.want {
+				t.Errorf("formatMermaidRelationship() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSanitizeType(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -63,7 +86,7 @@ func TestSanitizeType(t *testing.T) {
 func TestFormatMermaidAttribute_WithNote(t *testing.T) {
 	note := "test note"
 	attr := NewAttribute("ID", "string").WithNote(note)
-	output := formatMermaidAttribute(attr)
+	output := formatMermaidAttribute(attr, nil, RenderOptions{})
 
 	if !strings.Contains(output, note) {
 		t.Errorf("formatMermaidAttribute() should contain note %q, got %q", note, output)
@@ -73,7 +96,7 @@ func TestFormatMermaidAttribute_WithNote(t *testing.T) {
 func TestFormatMermaidAttribute_WithNullableAndNote(t *testing.T) {
 	note := "test note"
 	attr := NewAttribute("Name", "string").WithNullable().WithNote(note)
-	output := formatMermaidAttribute(attr)
+	output := formatMermaidAttribute(attr, nil, RenderOptions{})
 
 	if !strings.Contains(output, "nullable") {
 		t.Errorf("formatMermaidAttribute() should contain 'nullable', got %q", output)
@@ -82,3 +105,66 @@ func TestFormatMermaidAttribute_WithNullableAndNote(t *testing.T) {
 		t.Errorf("formatMermaidAttribute() should contain note %q, got %q", note, output)
 	}
 }
+
+func TestDiagram_WriteMermaid(t *testing.T) {
+	d := testOptionsDiagram()
+
+	var sb strings.Builder
+	if err := d.WriteMermaid(&sb); err != nil {
+		t.Fatalf("WriteMermaid() error = %v", err)
+	}
+	if sb.String() != d.ToMermaid() {
+		t.Errorf("WriteMermaid() = %q, want %q", sb.String(), d.ToMermaid())
+	}
+
+	sb.Reset()
+	opts := RenderOptions{KeysOnly: true}
+	if err := d.WriteMermaidWith(&sb, opts); err != nil {
+		t.Fatalf("WriteMermaidWith() error = %v", err)
+	}
+	if sb.String() != d.ToMermaidWith(opts) {
+		t.Errorf("WriteMermaidWith() = %q, want %q", sb.String(), d.ToMermaidWith(opts))
+	}
+}
+
+func TestDiagram_WriteMermaid_WriteError(t *testing.T) {
+	w := &limitedWriter{n: 2}
+	if err := testOptionsDiagram().WriteMermaid(w); err == nil {
+		t.Fatal("WriteMermaid() expected error from writer")
+	}
+	if w.written.String() != "erDiagram\n    Order {\n" {
+		t.Errorf("WriteMermaid() should stop after the failed write, got:\n%s", w.written.String())
+	}
+}
+
+func BenchmarkToMermaid(b *testing.B) {
+	d := benchmarkDiagram(5000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = d.ToMermaid()
+	}
+}
+
+func BenchmarkWriteMermaid(b *testing.B) {
+	d := benchmarkDiagram(5000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := d.WriteMermaid(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestToMermaid_CompositeKeys(t *testing.T) {
+	output := testCompositeDiagram().ToMermaid()
+
+	for _, exp := range []string{
+		"    LineItem {\n        string TenantID PK, FK\n        string OrderID PK, FK\n        string SKU PK\n    }\n",
+		"        string TenantID PK, FK, UK\n",
+		"        string Number UK\n",
+	} {
+		if !strings.Contains(output, exp) {
+			t.Errorf("ToMermaid() should contain %q, got:\n%s", exp, output)
+		}
+	}
+}
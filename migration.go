@@ -0,0 +1,469 @@
+package erd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Migration holds the SQL that moves a database between two diagrams.
+type Migration struct {
+	// Up migrates a database from the old diagram to the new one.
+	Up string
+	// Down rolls the migration back.
+	Down string
+}
+
+// MigrationSQL generates forward and rollback SQL for the changes from
+// before to after in the given dialect. Both scripts are empty when the
+// diagrams produce the same tables.
+//
+// Tables are compared as [Diagram.ToSQL] would create them, so new
+// many-to-many relationships create join tables and new fk-tagged
+// attributes add foreign key constraints. Renamed entities rename their
+// tables and constraints, along with the join tables and join columns of
+// their many-to-many relationships. Constraints are added and dropped by the
+// names ToSQL gives them (e.g. "Order_UserID_fkey"). New NOT NULL columns
+// have no default, so a warning comment precedes them. SQLite cannot alter
+// columns or constraints in place; those steps are emitted as comments
+// describing the table rebuild needed.
+func MigrationSQL(before, after *Diagram, dialect Dialect) (*Migration, error) {
+	if !isValidDialect(dialect) {
+		return nil, fmt.Errorf("unsupported SQL dialect: %q", dialect)
+	}
+
+	return &Migration{
+		Up:   migrationSQL(before, after, dialect),
+		Down: migrationSQL(after, before, dialect),
+	}, nil
+}
+
+// migrationSQL generates the statements migrating before to after.
+func migrationSQL(before, after *Diagram, dialect Dialect) string {
+	oldTables := before.sqlTables(dialect)
+	newTables := after.sqlTables(dialect)
+
+	// Table and join column renames, keyed by old table name
+	renames, columnRenames := tableRenames(before, after, oldTables, newTables, dialect)
+	rename := func(name string) string {
+		if to, ok := renames[name]; ok {
+			return to
+		}
+		return name
+	}
+
+	oldByOldName := make(map[string]*sqlTable, len(oldTables))
+	oldByName := make(map[string]*sqlTable, len(oldTables))
+	for _, table := range oldTables {
+		oldByOldName[table.Name] = table
+		oldByName[rename(table.Name)] = renameColumns(table, columnRenames[table.Name])
+	}
+	newByName := make(map[string]*sqlTable, len(newTables))
+	for _, table := range newTables {
+		newByName[table.Name] = table
+	}
+
+	var renameStmts, dropConstraints, creates, columns, addConstraints, dropColumns, drops []string
+
+	oldNames := make([]string, 0, len(renames)+len(columnRenames))
+	for name := range renames {
+		oldNames = append(oldNames, name)
+	}
+	for name := range columnRenames {
+		if _, ok := renames[name]; !ok {
+			oldNames = append(oldNames, name)
+		}
+	}
+	sort.Strings(oldNames)
+	var readdForeignKeys []string
+	for _, name := range oldNames {
+		table, old := rename(name), oldByOldName[name]
+		if table != name {
+			renameStmts = append(renameStmts, fmt.Sprintf("ALTER TABLE %s RENAME TO %s;",
+				quoteSQL(name, dialect), quoteSQL(table, dialect)))
+		}
+		if old != nil {
+			for _, col := range old.Columns {
+				if to, ok := columnRenames[name][col.Name]; ok {
+					renameStmts = append(renameStmts, fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;",
+						quoteSQL(table, dialect), quoteSQL(col.Name, dialect), quoteSQL(to, dialect)))
+				}
+			}
+		}
+		stmts, readds := renameConstraints(old, table, columnRenames[name], rename, dialect)
+		renameStmts = append(renameStmts, stmts...)
+		readdForeignKeys = append(readdForeignKeys, readds...)
+	}
+	renameStmts = append(renameStmts, readdForeignKeys...)
+
+	for _, table := range newTables {
+		old, ok := oldByName[table.Name]
+		if !ok {
+			creates = append(creates, strings.TrimSuffix(formatSQLTable(table, dialect), "\n"))
+			continue
+		}
+
+		m := &tableMigration{old: old, table: table, dialect: dialect, rename: rename}
+		m.diffColumns()
+		m.diffPrimaryKey()
+		m.diffUnique()
+		m.diffForeignKeys()
+
+		dropConstraints = append(dropConstraints, m.dropConstraints...)
+		columns = append(columns, m.columns...)
+		addConstraints = append(addConstraints, m.addConstraints...)
+		dropColumns = append(dropColumns, m.dropColumns...)
+	}
+
+	// Drop tables that referenced others first
+	for i := len(oldTables) - 1; i >= 0; i-- {
+		if _, ok := newByName[rename(oldTables[i].Name)]; !ok {
+			drops = append(drops, fmt.Sprintf("DROP TABLE %s;", quoteSQL(oldTables[i].Name, dialect)))
+		}
+	}
+
+	var groups []string
+	for _, stmts := range [][]string{renameStmts, dropConstraints, creates, columns, addConstraints, dropColumns, drops} {
+		if len(stmts) > 0 {
+			groups = append(groups, strings.Join(stmts, "\n")+"\n")
+		}
+	}
+	return strings.Join(groups, "\n")
+}
+
+// tableRenames maps the tables of entities renamed from before to after to
+// their new names. The join tables of their many-to-many relationships are
+// named after the entities too, so they are renamed along with them, and
+// join columns that change name are returned per old table name.
+func tableRenames(before, after *Diagram, oldTables, newTables []*sqlTable, dialect Dialect) (tables map[string]string, columns map[string]map[string]string) {
+	entities := detectRenames(before, after)
+	tables = make(map[string]string, len(entities))
+	columns = make(map[string]map[string]string)
+	for oldKey, newKey := range entities {
+		tables[sanitizeName(oldKey)] = sanitizeName(newKey)
+	}
+	renameEntity := func(name string) string {
+		if to, ok := entities[name]; ok {
+			return to
+		}
+		return name
+	}
+
+	oldNames := make(map[string]bool, len(oldTables))
+	for _, table := range oldTables {
+		oldNames[table.Name] = true
+	}
+	newNames := make(map[string]bool, len(newTables))
+	for _, table := range newTables {
+		newNames[table.Name] = true
+	}
+
+	for _, rel := range before.Relationships {
+		from, to := renameEntity(rel.From), renameEntity(rel.To)
+		if rel.Cardinality != ManyToMany || (from == rel.From && to == rel.To) {
+			continue
+		}
+		i := slices.IndexFunc(after.Relationships, func(next *Relationship) bool {
+			return next.Cardinality == ManyToMany && next.From == from && next.To == to && next.Field == rel.Field
+		})
+		if i < 0 {
+			continue
+		}
+		// Only the join tables ToSQL creates, one per pair of entities
+		old, next := before.sqlJoinTable(rel, dialect), after.sqlJoinTable(after.Relationships[i], dialect)
+		if old == nil || next == nil || !oldNames[old.Name] || !newNames[next.Name] {
+			continue
+		}
+		if old.Name != next.Name {
+			tables[old.Name] = next.Name
+		}
+		for j, col := range old.Columns {
+			if col.Name == next.Columns[j].Name {
+				continue
+			}
+			if columns[old.Name] == nil {
+				columns[old.Name] = make(map[string]string)
+			}
+			columns[old.Name][col.Name] = next.Columns[j].Name
+		}
+	}
+	return tables, columns
+}
+
+// renameColumns returns a copy of the table with its columns, and the
+// constraints on them, renamed.
+func renameColumns(table *sqlTable, renames map[string]string) *sqlTable {
+	if len(renames) == 0 {
+		return table
+	}
+	renamed := *table
+	renamed.Columns = make([]sqlColumn, len(table.Columns))
+	for i, col := range table.Columns {
+		col.Name = renamedColumns([]string{col.Name}, renames)[0]
+		renamed.Columns[i] = col
+	}
+	renamed.PrimaryKey = renamedColumns(table.PrimaryKey, renames)
+	renamed.Unique = make([][]string, len(table.Unique))
+	for i, cols := range table.Unique {
+		renamed.Unique[i] = renamedColumns(cols, renames)
+	}
+	renamed.ForeignKeys = make([]sqlForeignKey, len(table.ForeignKeys))
+	for i, fk := range table.ForeignKeys {
+		fk.Columns = renamedColumns(fk.Columns, renames)
+		renamed.ForeignKeys[i] = fk
+	}
+	return &renamed
+}
+
+// renamedColumns returns the column names with renames applied.
+func renamedColumns(columns []string, renames map[string]string) []string {
+	renamed := make([]string, len(columns))
+	for i, col := range columns {
+		if to, ok := renames[col]; ok {
+			col = to
+		}
+		renamed[i] = col
+	}
+	return renamed
+}
+
+// tableMigration collects the statements altering one table.
+type tableMigration struct {
+	old             *sqlTable
+	table           *sqlTable
+	rename          func(string) string
+	dialect         Dialect
+	dropConstraints []string
+	columns         []string
+	addConstraints  []string
+	dropColumns     []string
+}
+
+// alter formats an ALTER TABLE statement for the table.
+func (m *tableMigration) alter(action string) string {
+	return fmt.Sprintf("ALTER TABLE %s %s;", quoteSQL(m.table.Name, m.dialect), action)
+}
+
+// rebuild formats a comment for a change SQLite cannot make in place.
+func (m *tableMigration) rebuild(change string) string {
+	return fmt.Sprintf("-- SQLite cannot %s; rebuild table %s", change, quoteSQL(m.table.Name, m.dialect))
+}
+
+// diffColumns adds, alters and drops columns.
+func (m *tableMigration) diffColumns() {
+	oldCols := make(map[string]sqlColumn, len(m.old.Columns))
+	for _, col := range m.old.Columns {
+		oldCols[col.Name] = col
+	}
+	newCols := make(map[string]bool, len(m.table.Columns))
+
+	for _, col := range m.table.Columns {
+		newCols[col.Name] = true
+		old, ok := oldCols[col.Name]
+		if !ok {
+			if !col.Nullable {
+				m.columns = append(m.columns, fmt.Sprintf("-- WARNING: %s.%s is NOT NULL without a default; adding it fails if the table has rows",
+					quoteSQL(m.table.Name, m.dialect), quoteSQL(col.Name, m.dialect)))
+			}
+			m.columns = append(m.columns, m.alter("ADD COLUMN "+formatSQLColumn(col, m.dialect)))
+			continue
+		}
+		if old.Type == col.Type && old.Nullable == col.Nullable {
+			continue
+		}
+
+		name := quoteSQL(col.Name, m.dialect)
+		switch m.dialect {
+		case MySQL:
+			m.columns = append(m.columns, m.alter("MODIFY COLUMN "+formatSQLColumn(col, m.dialect)))
+		case SQLite:
+			m.columns = append(m.columns, m.rebuild(fmt.Sprintf("alter column %s to %s", name, strings.TrimPrefix(formatSQLColumn(col, m.dialect), name+" "))))
+		default:
+			if old.Type != col.Type {
+				m.columns = append(m.columns, m.alter(fmt.Sprintf("ALTER COLUMN %s TYPE %s", name, col.Type)))
+			}
+			if old.Nullable != col.Nullable {
+				action := "SET NOT NULL"
+				if col.Nullable {
+					action = "DROP NOT NULL"
+				}
+				m.columns = append(m.columns, m.alter(fmt.Sprintf("ALTER COLUMN %s %s", name, action)))
+			}
+		}
+	}
+
+	for _, col := range m.old.Columns {
+		if !newCols[col.Name] {
+			m.dropColumns = append(m.dropColumns, m.alter("DROP COLUMN "+quoteSQL(col.Name, m.dialect)))
+		}
+	}
+}
+
+// diffPrimaryKey replaces the primary key when its columns change.
+func (m *tableMigration) diffPrimaryKey() {
+	if len(m.old.PrimaryKey) == 0 && len(m.table.PrimaryKey) == 0 {
+		return
+	}
+	if sameColumns(m.old.PrimaryKey, m.table.PrimaryKey) {
+		return
+	}
+
+	if m.dialect == SQLite {
+		m.addConstraints = append(m.addConstraints, m.rebuild("change the primary key"))
+		return
+	}
+
+	if len(m.old.PrimaryKey) > 0 {
+		if m.dialect == MySQL {
+			m.dropConstraints = append(m.dropConstraints, m.alter("DROP PRIMARY KEY"))
+		} else {
+			m.dropConstraints = append(m.dropConstraints, m.alter("DROP CONSTRAINT "+quoteSQL(constraintName(m.table.Name, nil, "pkey"), m.dialect)))
+		}
+	}
+	if len(m.table.PrimaryKey) > 0 {
+		// MySQL always names the primary key PRIMARY
+		action := fmt.Sprintf("ADD PRIMARY KEY (%s)", quoteSQLList(m.table.PrimaryKey, m.dialect))
+		if m.dialect != MySQL {
+			action = fmt.Sprintf("ADD CONSTRAINT %s %s", quoteSQL(constraintName(m.table.Name, nil, "pkey"), m.dialect), action[len("ADD "):])
+		}
+		m.addConstraints = append(m.addConstraints, m.alter(action))
+	}
+}
+
+// diffUnique adds and drops unique constraints.
+func (m *tableMigration) diffUnique() {
+	for _, cols := range m.table.Unique {
+		if containsColumns(m.old.Unique, cols) {
+			continue
+		}
+		name := constraintName(m.table.Name, cols, "key")
+		switch m.dialect {
+		case PostgreSQL:
+			m.addConstraints = append(m.addConstraints, m.alter(fmt.Sprintf("ADD CONSTRAINT %s UNIQUE (%s)",
+				quoteSQL(name, m.dialect), quoteSQLList(cols, m.dialect))))
+		default:
+			m.addConstraints = append(m.addConstraints, fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s);",
+				quoteSQL(name, m.dialect), quoteSQL(m.table.Name, m.dialect), quoteSQLList(cols, m.dialect)))
+		}
+	}
+
+	for _, cols := range m.old.Unique {
+		if containsColumns(m.table.Unique, cols) {
+			continue
+		}
+		name := quoteSQL(constraintName(m.table.Name, cols, "key"), m.dialect)
+		switch m.dialect {
+		case PostgreSQL:
+			m.dropConstraints = append(m.dropConstraints, m.alter("DROP CONSTRAINT "+name))
+		case MySQL:
+			m.dropConstraints = append(m.dropConstraints, fmt.Sprintf("DROP INDEX %s ON %s;", name, quoteSQL(m.table.Name, m.dialect)))
+		default:
+			m.dropConstraints = append(m.dropConstraints, fmt.Sprintf("DROP INDEX %s;", name))
+		}
+	}
+}
+
+// diffForeignKeys adds and drops foreign key constraints.
+func (m *tableMigration) diffForeignKeys() {
+	key := func(fk sqlForeignKey, rename func(string) string) string {
+		return strings.Join(fk.Columns, ",") + ">" + rename(fk.RefTable) + "." + strings.Join(fk.RefColumns, ",")
+	}
+	same := func(name string) string { return name }
+
+	oldKeys := make(map[string]bool, len(m.old.ForeignKeys))
+	for _, fk := range m.old.ForeignKeys {
+		oldKeys[key(fk, m.rename)] = true
+	}
+	newKeys := make(map[string]bool, len(m.table.ForeignKeys))
+	for _, fk := range m.table.ForeignKeys {
+		newKeys[key(fk, same)] = true
+	}
+
+	for _, fk := range m.table.ForeignKeys {
+		if oldKeys[key(fk, same)] {
+			continue
+		}
+		if m.dialect == SQLite {
+			m.addConstraints = append(m.addConstraints, m.rebuild(fmt.Sprintf("add a foreign key on %s", quoteSQLList(fk.Columns, m.dialect))))
+			continue
+		}
+		m.addConstraints = append(m.addConstraints, m.alter(fmt.Sprintf("ADD CONSTRAINT %s %s",
+			quoteSQL(constraintName(m.table.Name, fk.Columns, "fkey"), m.dialect),
+			formatSQLForeignKey(fk, m.dialect))))
+	}
+
+	for _, fk := range m.old.ForeignKeys {
+		if newKeys[key(fk, m.rename)] {
+			continue
+		}
+		name := quoteSQL(constraintName(m.table.Name, fk.Columns, "fkey"), m.dialect)
+		switch m.dialect {
+		case SQLite:
+			m.dropConstraints = append(m.dropConstraints, m.rebuild(fmt.Sprintf("drop the foreign key on %s", quoteSQLList(fk.Columns, m.dialect))))
+		case MySQL:
+			m.dropConstraints = append(m.dropConstraints, m.alter("DROP FOREIGN KEY "+name))
+		default:
+			m.dropConstraints = append(m.dropConstraints, m.alter("DROP CONSTRAINT "+name))
+		}
+	}
+}
+
+// renameConstraints renames the constraints of a renamed table, or of a
+// table whose columns are renamed, after its new table and column names, so
+// they keep matching the names [Diagram.ToSQL] gives them. MySQL cannot
+// rename foreign keys, so they are dropped and returned to be added again
+// once every table has its new name. SQLite constraints are never altered
+// by name.
+func renameConstraints(old *sqlTable, table string, columns map[string]string, rename func(string) string, dialect Dialect) (stmts, readds []string) {
+	if old == nil || dialect == SQLite {
+		return nil, nil
+	}
+	alter := func(action string) string {
+		return fmt.Sprintf("ALTER TABLE %s %s;", quoteSQL(table, dialect), action)
+	}
+	names := func(cols []string, suffix string) (from, to string) {
+		return constraintName(old.Name, cols, suffix), constraintName(table, renamedColumns(cols, columns), suffix)
+	}
+	renamed := func(verb string, cols []string, suffix string) {
+		if from, to := names(cols, suffix); from != to {
+			stmts = append(stmts, alter(fmt.Sprintf("%s %s TO %s", verb, quoteSQL(from, dialect), quoteSQL(to, dialect))))
+		}
+	}
+
+	if dialect == PostgreSQL && len(old.PrimaryKey) > 0 {
+		renamed("RENAME CONSTRAINT", nil, "pkey")
+	}
+	for _, cols := range old.Unique {
+		if dialect == MySQL {
+			renamed("RENAME INDEX", cols, "key")
+		} else {
+			renamed("RENAME CONSTRAINT", cols, "key")
+		}
+	}
+	for _, fk := range old.ForeignKeys {
+		if dialect == PostgreSQL {
+			renamed("RENAME CONSTRAINT", fk.Columns, "fkey")
+			continue
+		}
+		from, to := names(fk.Columns, "fkey")
+		if from == to {
+			continue
+		}
+		stmts = append(stmts, alter("DROP FOREIGN KEY "+quoteSQL(from, dialect)))
+		fk.Columns = renamedColumns(fk.Columns, columns)
+		fk.RefTable = rename(fk.RefTable)
+		readds = append(readds, alter(fmt.Sprintf("ADD CONSTRAINT %s %s", quoteSQL(to, dialect), formatSQLForeignKey(fk, dialect))))
+	}
+	return stmts, readds
+}
+
+// containsColumns reports whether sets contains the column list.
+func containsColumns(sets [][]string, columns []string) bool {
+	for _, set := range sets {
+		if sameColumns(set, columns) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,407 @@
+package erd
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// testMigrationAfter evolves testSQLDiagram: Email becomes nullable, Phone
+// and Age are added, Bio is dropped, Shop is removed and a Tag entity joins
+// Order many-to-many.
+func testMigrationAfter() *Diagram {
+	after := testSQLDiagram()
+	user := after.Entities["User"]
+	user.Attributes[1].WithNullable()
+	user.Attributes = append(user.Attributes[:2],
+		NewAttribute("Phone", "string").WithNullable(),
+		NewAttribute("Age", "int32").WithNullable().WithUnique())
+	delete(after.Entities, "Shop")
+	after.AddEntity(NewEntity("Tag").AddAttribute(NewAttribute("ID", "int64").WithPrimaryKey()))
+	after.AddRelationship(NewRelationship("Order", "Tag", "Tags", ManyToMany))
+	return after
+}
+
+func TestMigrationSQL_PostgreSQL(t *testing.T) {
+	m, err := MigrationSQL(testSQLDiagram(), testMigrationAfter(), PostgreSQL)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+
+	wantUp := `ALTER TABLE "Order" DROP CONSTRAINT "Order_ShopID_fkey";
+
+CREATE TABLE "Tag" (
+    "ID" BIGINT NOT NULL,
+    CONSTRAINT "Tag_pkey" PRIMARY KEY ("ID")
+);
+CREATE TABLE "Order_Tags" (
+    "OrderID" BIGINT NOT NULL,
+    "TagID" BIGINT NOT NULL,
+    CONSTRAINT "Order_Tags_pkey" PRIMARY KEY ("OrderID", "TagID"),
+    CONSTRAINT "Order_Tags_OrderID_fkey" FOREIGN KEY ("OrderID") REFERENCES "Order" ("ID"),
+    CONSTRAINT "Order_Tags_TagID_fkey" FOREIGN KEY ("TagID") REFERENCES "Tag" ("ID")
+);
+
+ALTER TABLE "User" ALTER COLUMN "Email" DROP NOT NULL;
+ALTER TABLE "User" ADD COLUMN "Phone" TEXT;
+ALTER TABLE "User" ADD COLUMN "Age" INTEGER;
+
+ALTER TABLE "User" ADD CONSTRAINT "User_Age_key" UNIQUE ("Age");
+
+ALTER TABLE "User" DROP COLUMN "Bio";
+
+DROP TABLE "Shop";
+`
+	if m.Up != wantUp {
+		t.Errorf("Up =\n%s\nwant\n%s", m.Up, wantUp)
+	}
+
+	wantDown := []string{
+		`ALTER TABLE "User" DROP CONSTRAINT "User_Age_key";`,
+		"CREATE TABLE \"Shop\" (\n",
+		`ALTER TABLE "User" ALTER COLUMN "Email" SET NOT NULL;`,
+		`ALTER TABLE "User" ADD COLUMN "Bio" TEXT;`,
+		`ALTER TABLE "Order" ADD CONSTRAINT "Order_ShopID_fkey" FOREIGN KEY ("ShopID") REFERENCES "Shop" ("ID");`,
+		`ALTER TABLE "User" DROP COLUMN "Phone";`,
+		"DROP TABLE \"Order_Tags\";\nDROP TABLE \"Tag\";\n",
+	}
+	assertInOrder(t, m.Down, wantDown)
+}
+
+func TestMigrationSQL_MySQL(t *testing.T) {
+	m, err := MigrationSQL(testSQLDiagram(), testMigrationAfter(), MySQL)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+
+	assertInOrder(t, m.Up, []string{
+		"ALTER TABLE `Order` DROP FOREIGN KEY `Order_ShopID_fkey`;",
+		"CREATE TABLE `Tag` (",
+		"ALTER TABLE `User` MODIFY COLUMN `Email` VARCHAR(255);",
+		"ALTER TABLE `User` ADD COLUMN `Phone` VARCHAR(255);",
+		"CREATE UNIQUE INDEX `User_Age_key` ON `User` (`Age`);",
+		"ALTER TABLE `User` DROP COLUMN `Bio`;",
+		"DROP TABLE `Shop`;",
+	})
+	assertInOrder(t, m.Down, []string{
+		"DROP INDEX `User_Age_key` ON `User`;",
+		"ALTER TABLE `User` MODIFY COLUMN `Email` VARCHAR(255) NOT NULL;",
+		"ALTER TABLE `Order` ADD CONSTRAINT `Order_ShopID_fkey` FOREIGN KEY (`ShopID`) REFERENCES `Shop` (`ID`);",
+	})
+}
+
+func TestMigrationSQL_SQLite(t *testing.T) {
+	m, err := MigrationSQL(testSQLDiagram(), testMigrationAfter(), SQLite)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+
+	assertInOrder(t, m.Up, []string{
+		`-- SQLite cannot drop the foreign key on "ShopID"; rebuild table "Order"`,
+		`-- SQLite cannot alter column "Email" to TEXT; rebuild table "User"`,
+		`CREATE UNIQUE INDEX "User_Age_key" ON "User" ("Age");`,
+	})
+	assertInOrder(t, m.Down, []string{
+		`DROP INDEX "User_Age_key";`,
+		`-- SQLite cannot add a foreign key on "ShopID"; rebuild table "Order"`,
+	})
+}
+
+func TestMigrationSQL_ApplySQLite(t *testing.T) {
+	// Changes SQLite can apply in place: new and dropped tables and columns
+	before := testSQLDiagram()
+	after := testSQLDiagram()
+	after.Entities["User"].AddAttribute(NewAttribute("Phone", "string").WithNullable().WithUnique())
+	after.Entities["Group"].AddAttribute(NewAttribute("Name", "string").WithNullable())
+	after.AddEntity(NewEntity("Tag").AddAttribute(NewAttribute("ID", "int64").WithPrimaryKey()))
+	after.AddRelationship(NewRelationship("Order", "Tag", "Tags", ManyToMany))
+
+	ddl, err := before.ToSQL(SQLite)
+	if err != nil {
+		t.Fatalf("ToSQL() unexpected error: %v", err)
+	}
+	m, err := MigrationSQL(before, after, SQLite)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+	if strings.Contains(m.Up, "-- SQLite cannot") || strings.Contains(m.Down, "-- SQLite cannot") {
+		t.Fatalf("expected an in-place migration, got:\n%s\n%s", m.Up, m.Down)
+	}
+
+	db := openTestSQLite(t, ddl)
+	ctx := context.Background()
+	introspect := func() *Diagram {
+		d, err := FromDatabase(ctx, db, DatabaseOptions{Title: "Shop", Dialect: SQLite})
+		if err != nil {
+			t.Fatalf("FromDatabase() unexpected error: %v", err)
+		}
+		return d
+	}
+	initial := introspect()
+
+	if _, err := db.ExecContext(ctx, m.Up); err != nil {
+		t.Fatalf("applying Up: %v\n%s", err, m.Up)
+	}
+	migrated := introspect()
+	if _, ok := migrated.Entities["Tag"]; !ok {
+		t.Error("expected table Tag after Up")
+	}
+	joined := false
+	for _, rel := range migrated.Relationships {
+		if rel.From == "Order" && rel.To == "Tag" && rel.Cardinality == ManyToMany {
+			joined = true
+		}
+	}
+	if !joined {
+		t.Error("expected Order_Tags join table after Up")
+	}
+	if attr := findAttribute(migrated.Entities["User"], "Phone"); attr == nil || attr.Key == nil || *attr.Key != UniqueKey {
+		t.Errorf("expected unique Phone column after Up, got %+v", attr)
+	}
+
+	if _, err := db.ExecContext(ctx, m.Down); err != nil {
+		t.Fatalf("applying Down: %v\n%s", err, m.Down)
+	}
+	if cs := Diff(initial, introspect()); !cs.Empty() {
+		t.Errorf("Down should restore the original schema, got:\n%s", cs)
+	}
+}
+
+func TestMigrationSQL_Rename(t *testing.T) {
+	after := testSQLDiagram()
+	group := after.Entities["Group"]
+	delete(after.Entities, "Group")
+	group.Name = "Team"
+	after.AddEntity(group)
+
+	m, err := MigrationSQL(testSQLDiagram(), after, PostgreSQL)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+	assertInOrder(t, m.Up, []string{`ALTER TABLE "Group" RENAME TO "Team";`})
+	assertInOrder(t, m.Down, []string{`ALTER TABLE "Team" RENAME TO "Group";`})
+	if strings.Contains(m.Up, `DROP TABLE "Group"`) {
+		t.Errorf("renamed table should not be dropped, got:\n%s", m.Up)
+	}
+}
+
+func TestMigrationSQL_RenameJoinTable(t *testing.T) {
+	// User is renamed to Member, taking its many-to-many join table along
+	after := testSQLDiagram()
+	user := after.Entities["User"]
+	delete(after.Entities, "User")
+	user.Name = "Member"
+	after.AddEntity(user)
+	after.Relationships[0].From = "Member"
+	after.Relationships[1].From = "Member"
+
+	m, err := MigrationSQL(testSQLDiagram(), after, PostgreSQL)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+	assertInOrder(t, m.Up, []string{
+		`ALTER TABLE "User" RENAME TO "Member";`,
+		`ALTER TABLE "User_Groups" RENAME TO "Member_Groups";`,
+		`ALTER TABLE "Member_Groups" RENAME COLUMN "UserID" TO "MemberID";`,
+		`ALTER TABLE "Member_Groups" RENAME CONSTRAINT "User_Groups_pkey" TO "Member_Groups_pkey";`,
+		`ALTER TABLE "Member_Groups" RENAME CONSTRAINT "User_Groups_UserID_fkey" TO "Member_Groups_MemberID_fkey";`,
+		`ALTER TABLE "Member_Groups" RENAME CONSTRAINT "User_Groups_GroupID_fkey" TO "Member_Groups_GroupID_fkey";`,
+	})
+	assertInOrder(t, m.Down, []string{
+		`ALTER TABLE "Member_Groups" RENAME TO "User_Groups";`,
+		`ALTER TABLE "User_Groups" RENAME COLUMN "MemberID" TO "UserID";`,
+	})
+	for _, script := range []string{m.Up, m.Down} {
+		if strings.Contains(script, "DROP TABLE") || strings.Contains(script, "CREATE TABLE") || strings.Contains(script, "COLUMN \"GroupID\"") {
+			t.Errorf("join table should be renamed in place, got:\n%s", script)
+		}
+	}
+
+	// SQLite renames the table and column in place
+	ddl, err := testSQLDiagram().ToSQL(SQLite)
+	if err != nil {
+		t.Fatalf("ToSQL() unexpected error: %v", err)
+	}
+	lite, err := MigrationSQL(testSQLDiagram(), after, SQLite)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+	db := openTestSQLite(t, ddl)
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, lite.Up); err != nil {
+		t.Fatalf("applying Up: %v\n%s", err, lite.Up)
+	}
+	migrated, err := FromDatabase(ctx, db, DatabaseOptions{Dialect: SQLite})
+	if err != nil {
+		t.Fatalf("FromDatabase() unexpected error: %v", err)
+	}
+	joined := false
+	for _, rel := range migrated.Relationships {
+		if rel.From == "Member" && rel.To == "Group" && rel.Cardinality == ManyToMany {
+			joined = true
+		}
+	}
+	if !joined {
+		t.Errorf("expected Member_Groups join table after Up, got %v", migrated.entityNames())
+	}
+}
+
+func TestMigrationSQL_RenameConstraints(t *testing.T) {
+	// Order is renamed to Purchase, keeping its keys
+	after := testSQLDiagram()
+	order := after.Entities["Order"]
+	delete(after.Entities, "Order")
+	order.Name = "Purchase"
+	after.AddEntity(order)
+	after.Relationships[0].To = "Purchase"
+
+	pg, err := MigrationSQL(testSQLDiagram(), after, PostgreSQL)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+	assertInOrder(t, pg.Up, []string{
+		`ALTER TABLE "Order" RENAME TO "Purchase";`,
+		`ALTER TABLE "Purchase" RENAME CONSTRAINT "Order_pkey" TO "Purchase_pkey";`,
+		`ALTER TABLE "Purchase" RENAME CONSTRAINT "Order_UserID_fkey" TO "Purchase_UserID_fkey";`,
+		`ALTER TABLE "Purchase" RENAME CONSTRAINT "Order_ShopID_fkey" TO "Purchase_ShopID_fkey";`,
+	})
+	assertInOrder(t, pg.Down, []string{
+		`ALTER TABLE "Purchase" RENAME TO "Order";`,
+		`ALTER TABLE "Order" RENAME CONSTRAINT "Purchase_pkey" TO "Order_pkey";`,
+	})
+
+	mysql, err := MigrationSQL(testSQLDiagram(), after, MySQL)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+	assertInOrder(t, mysql.Up, []string{
+		"ALTER TABLE `Order` RENAME TO `Purchase`;",
+		"ALTER TABLE `Purchase` DROP FOREIGN KEY `Order_UserID_fkey`;",
+		"ALTER TABLE `Purchase` ADD CONSTRAINT `Purchase_UserID_fkey` FOREIGN KEY (`UserID`) REFERENCES `User` (`ID`);",
+	})
+	if strings.Contains(mysql.Up, "RENAME CONSTRAINT") || strings.Contains(mysql.Up, "`Order_pkey`") {
+		t.Errorf("MySQL should not rename constraints in place, got:\n%s", mysql.Up)
+	}
+}
+
+func TestMigrationSQL_ConstraintNamesMatchToSQL(t *testing.T) {
+	// Rolling back drops the constraints ToSQL created, by name
+	after := testSQLDiagram()
+	after.Entities["User"].AddAttribute(NewAttribute("Handle", "string").WithNullable().WithUnique())
+
+	for _, dialect := range []Dialect{PostgreSQL, MySQL} {
+		t.Run(string(dialect), func(t *testing.T) {
+			ddl, err := after.ToSQL(dialect)
+			if err != nil {
+				t.Fatalf("ToSQL() unexpected error: %v", err)
+			}
+			m, err := MigrationSQL(after, testSQLDiagram(), dialect)
+			if err != nil {
+				t.Fatalf("MigrationSQL() unexpected error: %v", err)
+			}
+			name := quoteSQL("User_Handle_key", dialect)
+			if !strings.Contains(ddl, "CONSTRAINT "+name+" UNIQUE") {
+				t.Errorf("ToSQL() should name the unique constraint %s, got:\n%s", name, ddl)
+			}
+			if !strings.Contains(m.Up, name) {
+				t.Errorf("MigrationSQL() should drop %s, got:\n%s", name, m.Up)
+			}
+		})
+	}
+}
+
+func TestConstraintName(t *testing.T) {
+	tests := []struct {
+		table   string
+		suffix  string
+		want    string
+		columns []string
+	}{
+		{"Order", "pkey", "Order_pkey", nil},
+		{"User", "key", "User_Email_key", []string{"Email"}},
+		{"Order", "fkey", "Order_TenantID_OrderID_fkey", []string{"TenantID", "OrderID"}},
+	}
+
+	for _, tt := range tests {
+		if got := constraintName(tt.table, tt.columns, tt.suffix); got != tt.want {
+			t.Errorf("constraintName(%q, %v, %q) = %q, want %q", tt.table, tt.columns, tt.suffix, got, tt.want)
+		}
+	}
+
+	// Long names are cut to 63 characters and stay distinct
+	table := strings.Repeat("t", 70)
+	pkey := constraintName(table, nil, "pkey")
+	first := constraintName(table, []string{"FirstID"}, "fkey")
+	second := constraintName(table, []string{"SecondID"}, "fkey")
+	for _, name := range []string{pkey, first, second} {
+		if len(name) != 63 || !strings.HasPrefix(name, strings.Repeat("t", 54)) {
+			t.Errorf("constraintName() = %q, want 63 characters starting with the table", name)
+		}
+	}
+	if pkey == first || first == second {
+		t.Errorf("long constraint names collide: %q, %q, %q", pkey, first, second)
+	}
+	if again := constraintName(table, []string{"FirstID"}, "fkey"); again != first {
+		t.Errorf("constraintName() = %q, then %q; want a stable name", first, again)
+	}
+}
+
+func TestMigrationSQL_PrimaryKey(t *testing.T) {
+	after := testSQLDiagram()
+	shop := after.Entities["Shop"]
+	shop.Attributes[0].Key = nil
+	shop.AddAttribute(NewAttribute("Code", "string").WithPrimaryKey())
+
+	pg, err := MigrationSQL(testSQLDiagram(), after, PostgreSQL)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+	assertInOrder(t, pg.Up, []string{
+		`ALTER TABLE "Shop" DROP CONSTRAINT "Shop_pkey";`,
+		`-- WARNING: "Shop"."Code" is NOT NULL without a default; adding it fails if the table has rows`,
+		`ALTER TABLE "Shop" ADD COLUMN "Code" TEXT NOT NULL;`,
+		`ALTER TABLE "Shop" ADD CONSTRAINT "Shop_pkey" PRIMARY KEY ("Code");`,
+	})
+
+	mysql, err := MigrationSQL(testSQLDiagram(), after, MySQL)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+	assertInOrder(t, mysql.Up, []string{"ALTER TABLE `Shop` DROP PRIMARY KEY;", "ALTER TABLE `Shop` ADD PRIMARY KEY (`Code`);"})
+
+	sqlite, err := MigrationSQL(testSQLDiagram(), after, SQLite)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+	assertInOrder(t, sqlite.Up, []string{`-- SQLite cannot change the primary key; rebuild table "Shop"`})
+}
+
+func TestMigrationSQL_NoChanges(t *testing.T) {
+	m, err := MigrationSQL(testSQLDiagram(), testSQLDiagram(), PostgreSQL)
+	if err != nil {
+		t.Fatalf("MigrationSQL() unexpected error: %v", err)
+	}
+	if m.Up != "" || m.Down != "" {
+		t.Errorf("expected empty migration, got Up %q, Down %q", m.Up, m.Down)
+	}
+}
+
+func TestMigrationSQL_InvalidDialect(t *testing.T) {
+	if _, err := MigrationSQL(testSQLDiagram(), testMigrationAfter(), "oracle"); err == nil {
+		t.Error("MigrationSQL() expected error for unsupported dialect")
+	}
+}
+
+// assertInOrder checks that output contains each expected string, in order.
+func assertInOrder(t *testing.T, output string, expected []string) {
+	t.Helper()
+	rest := output
+	for _, exp := range expected {
+		i := strings.Index(rest, exp)
+		if i < 0 {
+			t.Errorf("output should contain %q after the previous match, got:\n%s", exp, output)
+			return
+		}
+		rest = rest[i+len(exp):]
+	}
+}
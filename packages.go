@@ -0,0 +1,206 @@
+package erd
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/zoobzio/sentinel"
+	"golang.org/x/tools/go/packages"
+)
+
+// FromPackages generates an ERD diagram by statically analyzing Go source.
+//
+// The patterns are resolved like `go list` patterns (for example "./models"
+// or "./..."). Every exported, non-generic struct type declared in the
+// matched packages becomes an entity. Fields, erd tags and relationships are
+// derived exactly as [FromSchema] derives them from a sentinel scan, so no
+// custom binary has to be compiled and run. Relationships are only inferred
+// to struct types declared in the matched packages.
+//
+// The diagram title is the list of matched package names; set [Diagram.Title]
+// to override it.
+func FromPackages(patterns ...string) (*Diagram, error) {
+	return FromPackagesWith(SchemaOptions{}, patterns...)
+}
+
+// FromPackagesWith generates an ERD diagram by statically analyzing Go
+// source, as [FromPackages] does, using the given options.
+func FromPackagesWith(opts SchemaOptions, patterns ...string) (*Diagram, error) {
+	// Dependencies are type-checked from source rather than read from
+	// compiler export data, whose format varies between Go toolchains.
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax,
+	}
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("loading packages: %w", err)
+	}
+
+	var errs []string
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, e := range pkg.Errors {
+			errs = append(errs, e.Error())
+		}
+	})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("loading packages: %s", strings.Join(errs, "; "))
+	}
+
+	// The domain is every package matched by the patterns
+	domain := make(map[string]bool, len(pkgs))
+	names := make([]string, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		if pkg.Types == nil || domain[pkg.PkgPath] {
+			continue
+		}
+		domain[pkg.PkgPath] = true
+		names = append(names, pkg.Name)
+	}
+
+	schema := make(map[string]sentinel.Metadata)
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || !tn.Exported() || tn.IsAlias() {
+				continue
+			}
+			named, ok := tn.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 {
+				continue
+			}
+			st, ok := named.Underlying().(*types.Struct)
+			if !ok {
+				continue
+			}
+			meta := metadataFromStruct(tn, st, domain)
+			schema[meta.FQDN] = meta
+		}
+	}
+
+	return FromSchemaWith(strings.Join(names, ", "), schema, opts), nil
+}
+
+// metadataFromStruct builds sentinel metadata for a struct type, mirroring
+// what sentinel extracts via reflection.
+func metadataFromStruct(tn *types.TypeName, st *types.Struct, domain map[string]bool) sentinel.Metadata {
+	pkgPath := tn.Pkg().Path()
+	meta := sentinel.Metadata{
+		FQDN:        pkgPath + "." + tn.Name(),
+		TypeName:    tn.Name(),
+		PackageName: pkgPath,
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Exported() {
+			continue
+		}
+
+		fieldMeta := sentinel.FieldMetadata{
+			Name: field.Name(),
+			Type: types.TypeString(field.Type(), packageNameQualifier),
+			Kind: fieldKindOf(field.Type()),
+			Tags: make(map[string]string),
+		}
+		if tag := reflect.StructTag(st.Tag(i)).Get("erd"); tag != "" {
+			fieldMeta.Tags["erd"] = tag
+		}
+		meta.Fields = append(meta.Fields, fieldMeta)
+
+		if rel, ok := relationshipFromField(field, domain); ok {
+			rel.From = meta.FQDN
+			meta.Relationships = append(meta.Relationships, rel)
+		}
+	}
+
+	return meta
+}
+
+// relationshipFromField detects a relationship to a struct type in the
+// domain, using the same rules sentinel applies to reflected fields.
+func relationshipFromField(field *types.Var, domain map[string]bool) (sentinel.TypeRelationship, bool) {
+	var target types.Type
+	var kind string
+
+	switch t := types.Unalias(field.Type()).(type) {
+	case *types.Named:
+		target = t
+		kind = sentinel.RelationshipReference
+		if field.Anonymous() {
+			kind = sentinel.RelationshipEmbedding
+		}
+	case *types.Pointer:
+		target = types.Unalias(t.Elem())
+		kind = sentinel.RelationshipReference
+	case *types.Slice:
+		target = derefType(t.Elem())
+		kind = sentinel.RelationshipCollection
+	case *types.Array:
+		target = derefType(t.Elem())
+		kind = sentinel.RelationshipCollection
+	case *types.Map:
+		target = derefType(t.Elem())
+		kind = sentinel.RelationshipMap
+	default:
+		return sentinel.TypeRelationship{}, false
+	}
+
+	named, ok := target.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return sentinel.TypeRelationship{}, false
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return sentinel.TypeRelationship{}, false
+	}
+	pkgPath := named.Obj().Pkg().Path()
+	if !domain[pkgPath] {
+		return sentinel.TypeRelationship{}, false
+	}
+
+	return sentinel.TypeRelationship{
+		To:        pkgPath + "." + named.Obj().Name(),
+		Field:     field.Name(),
+		Kind:      kind,
+		ToPackage: pkgPath,
+	}, true
+}
+
+// derefType strips one level of pointer indirection.
+func derefType(t types.Type) types.Type {
+	t = types.Unalias(t)
+	if p, ok := t.(*types.Pointer); ok {
+		return types.Unalias(p.Elem())
+	}
+	return t
+}
+
+// fieldKindOf categorizes a type the way sentinel categorizes reflected fields.
+func fieldKindOf(t types.Type) sentinel.FieldKind {
+	switch t.Underlying().(type) {
+	case *types.Pointer:
+		return sentinel.KindPointer
+	case *types.Slice, *types.Array:
+		return sentinel.KindSlice
+	case *types.Struct:
+		return sentinel.KindStruct
+	case *types.Map:
+		return sentinel.KindMap
+	case *types.Interface:
+		return sentinel.KindInterface
+	default:
+		return sentinel.KindScalar
+	}
+}
+
+// packageNameQualifier qualifies types by package name, matching the
+// output of reflect.Type.String.
+func packageNameQualifier(pkg *types.Package) string {
+	return pkg.Name()
+}
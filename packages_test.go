@@ -0,0 +1,94 @@
+package erd
+
+import "testing"
+
+func TestFromPackages(t *testing.T) {
+	diagram, err := FromPackages("./testdata/models")
+	if err != nil {
+		t.Fatalf("FromPackages() unexpected error: %v", err)
+	}
+
+	if diagram.Title != "models" {
+		t.Errorf("expected title 'models', got %q", diagram.Title)
+	}
+
+	if len(diagram.Entities) != 7 {
+		t.Errorf("expected 7 entities, got %d", len(diagram.Entities))
+	}
+	for _, name := range []string{"Status", "Page", "internal"} {
+		if _, ok := diagram.Entities[name]; ok {
+			t.Errorf("%s should not be an entity", name)
+		}
+	}
+
+	user, ok := diagram.Entities["User"]
+	if !ok {
+		t.Fatal("User entity not found")
+	}
+	if user.Package == nil || *user.Package != "github.com/zoobzio/erd/testdata/models" {
+		t.Errorf("expected User package path, got %v", user.Package)
+	}
+
+	// Relationship and unexported fields are excluded from attributes
+	if len(user.Attributes) != 4 {
+		t.Errorf("expected 4 attributes on User, got %d", len(user.Attributes))
+	}
+	if id := findAttribute(user, "ID"); id == nil || id.Key == nil || *id.Key != PrimaryKey {
+		t.Error("expected User.ID to be marked as primary key")
+	}
+	if created := findAttribute(user, "CreatedAt"); created == nil || created.Type != "time.Time" {
+		t.Errorf("expected CreatedAt of type time.Time, got %+v", created)
+	}
+
+	profile := diagram.Entities["Profile"]
+	if bio := findAttribute(profile, "Bio"); bio == nil || !bio.Nullable || bio.Type != "string" {
+		t.Errorf("expected Bio to be a nullable string, got %+v", bio)
+	}
+	if avatar := findAttribute(profile, "Avatar"); avatar == nil || avatar.Note == nil || *avatar.Note != "Profile picture URL" {
+		t.Errorf("expected Avatar note, got %+v", avatar)
+	}
+
+	tests := []struct {
+		field string
+		to    string
+		want  Cardinality
+	}{
+		{"Profile", "Profile", OneToOne},
+		{"Orders", "Order", OneToMany},
+		{"Lines", "LineItem", OneToMany},
+		{"Address", "Address", OneToOne},
+		{"Employees", "Employee", ManyToMany},
+	}
+	for _, tt := range tests {
+		var rel *Relationship
+		for _, r := range diagram.Relationships {
+			if r.Field == tt.field {
+				rel = r
+				break
+			}
+		}
+		if rel == nil {
+			t.Errorf("%s relationship not found", tt.field)
+			continue
+		}
+		if rel.From == "" || rel.To != tt.to {
+			t.Errorf("%s relationship points to %q", tt.field, rel.To)
+		}
+		if rel.Cardinality != tt.want {
+			t.Errorf("%s relationship = %s, want %s", tt.field, rel.Cardinality, tt.want)
+		}
+	}
+	if len(diagram.Relationships) != len(tests) {
+		t.Errorf("expected %d relationships, got %d", len(tests), len(diagram.Relationships))
+	}
+
+	if errs := diagram.Validate(); len(errs) > 0 {
+		t.Errorf("Validate() unexpected errors: %v", errs)
+	}
+}
+
+func TestFromPackages_Errors(t *testing.T) {
+	if _, err := FromPackages("./testdata/does-not-exist"); err == nil {
+		t.Error("FromPackages() expected error for missing package")
+	}
+}
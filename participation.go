@@ -0,0 +1,83 @@
+package erd
+
+import "fmt"
+
+// Participation returns the participation at each end of the relationship,
+// filling in ends that are not set from its cardinality.
+func (r *Relationship) Participation() (from, to Participation) {
+	fromMany, toMany := cardinalityEnds(r.Cardinality)
+	from, to = defaultParticipation(fromMany), defaultParticipation(toMany)
+	if r.FromParticipation != nil {
+		from = *r.FromParticipation
+	}
+	if r.ToParticipation != nil {
+		to = *r.ToParticipation
+	}
+	return from, to
+}
+
+// hasParticipation reports whether either end of the relationship sets its
+// participation.
+func (r *Relationship) hasParticipation() bool {
+	return r.FromParticipation != nil || r.ToParticipation != nil
+}
+
+// defaultParticipation is the participation of an end that is not set:
+// zero or more for a many end and exactly one otherwise.
+func defaultParticipation(many bool) Participation {
+	if many {
+		return ZeroOrMore
+	}
+	return ExactlyOne
+}
+
+// many reports whether an end allows more than one entity.
+func (p Participation) many() bool {
+	return p == ZeroOrMore || p == OneOrMore
+}
+
+// crowsFootEnds maps participation to the crow's foot symbols Mermaid and
+// PlantUML draw at the From and To end of a relationship.
+var crowsFootEnds = map[Participation][2]string{
+	ZeroOrOne:  {"|o", "o|"},
+	ExactlyOne: {"||", "||"},
+	ZeroOrMore: {"}o", "o{"},
+	OneOrMore:  {"}|", "|{"},
+}
+
+// crowsFoot returns the crow's foot connector for the participation at each
+// end, such as "||--o{". Unknown participation is drawn as exactly one.
+func crowsFoot(from, to Participation) string {
+	fromEnd, ok := crowsFootEnds[from]
+	if !ok {
+		fromEnd = crowsFootEnds[ExactlyOne]
+	}
+	toEnd, ok := crowsFootEnds[to]
+	if !ok {
+		toEnd = crowsFootEnds[ExactlyOne]
+	}
+	return fromEnd[0] + "--" + toEnd[1]
+}
+
+// dotArrows maps participation to the GraphViz arrow shape drawn at an end
+// of an edge: the maximum nearest the node, then the minimum.
+var dotArrows = map[Participation]string{
+	ZeroOrOne:  "teeodot",
+	ExactlyOne: "teetee",
+	ZeroOrMore: "crowodot",
+	OneOrMore:  "crowtee",
+}
+
+// getDOTParticipation returns DOT edge styling for the participation at
+// each end. Unknown participation is drawn as exactly one.
+func getDOTParticipation(from, to Participation) string {
+	head, ok := dotArrows[to]
+	if !ok {
+		head = dotArrows[ExactlyOne]
+	}
+	tail, ok := dotArrows[from]
+	if !ok {
+		tail = dotArrows[ExactlyOne]
+	}
+	return fmt.Sprintf("arrowhead=%s, arrowtail=%s, dir=both", head, tail)
+}
@@ -0,0 +1,79 @@
+package erd
+
+import "testing"
+
+func TestRelationship_Participation(t *testing.T) {
+	tests := []struct {
+		rel      *Relationship
+		name     string
+		wantFrom Participation
+		wantTo   Participation
+	}{
+		{NewRelationship("User", "Profile", "Profile", OneToOne), "one to one", ExactlyOne, ExactlyOne},
+		{NewRelationship("User", "Order", "Orders", OneToMany), "one to many", ExactlyOne, ZeroOrMore},
+		{NewRelationship("Order", "User", "User", ManyToOne), "many to one", ZeroOrMore, ExactlyOne},
+		{NewRelationship("User", "Group", "Groups", ManyToMany), "many to many", ZeroOrMore, ZeroOrMore},
+		{
+			NewRelationship("User", "Profile", "Profile", OneToOne).WithToParticipation(ZeroOrOne),
+			"optional to end", ExactlyOne, ZeroOrOne,
+		},
+		{
+			NewRelationship("User", "Order", "Orders", OneToMany).
+				WithFromParticipation(ZeroOrOne).
+				WithToParticipation(OneOrMore),
+			"both ends set", ZeroOrOne, OneOrMore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := tt.rel.Participation()
+			if from != tt.wantFrom || to != tt.wantTo {
+				t.Errorf("Participation() = %s, %s, want %s, %s", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestCrowsFoot(t *testing.T) {
+	tests := []struct {
+		from Participation
+		to   Participation
+		want string
+	}{
+		{ExactlyOne, ZeroOrOne, "||--o|"},
+		{ExactlyOne, OneOrMore, "||--|{"},
+		{ZeroOrOne, ExactlyOne, "|o--||"},
+		{OneOrMore, ZeroOrMore, "}|--o{"},
+		{Participation("unknown"), ZeroOrMore, "||--o{"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" "+string(tt.to), func(t *testing.T) {
+			if got := crowsFoot(tt.from, tt.to); got != tt.want {
+				t.Errorf("crowsFoot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDOTParticipation(t *testing.T) {
+	tests := []struct {
+		from Participation
+		to   Participation
+		want string
+	}{
+		{ExactlyOne, ZeroOrOne, "arrowhead=teeodot, arrowtail=teetee, dir=both"},
+		{ExactlyOne, OneOrMore, "arrowhead=crowtee, arrowtail=teetee, dir=both"},
+		{ZeroOrMore, ExactlyOne, "arrowhead=teetee, arrowtail=crowodot, dir=both"},
+		{Participation("unknown"), ZeroOrMore, "arrowhead=crowodot, arrowtail=teetee, dir=both"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" "+string(tt.to), func(t *testing.T) {
+			if got := getDOTParticipation(tt.from, tt.to); got != tt.want {
+				t.Errorf("getDOTParticipation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
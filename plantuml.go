@@ -0,0 +1,160 @@
+package erd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ToPlantUML generates a PlantUML entity-relationship diagram from the diagram structure.
+func (d *Diagram) ToPlantUML() string {
+	return d.ToPlantUMLWith(RenderOptions{})
+}
+
+// ToPlantUMLWith generates a PlantUML entity-relationship diagram using the given options.
+func (d *Diagram) ToPlantUMLWith(opts RenderOptions) string {
+	var sb strings.Builder
+
+	sb.WriteString("@startuml\n")
+
+	// Add title if present
+	if d.Title != "" && !opts.HideTitle {
+		sb.WriteString(fmt.Sprintf("title %s\n", escapePlantUML(d.Title)))
+	}
+
+	sb.WriteString("\n")
+
+	// Write entities with their attributes (sorted by name for deterministic output)
+	ungrouped, groups := d.entityGroups(opts)
+	for _, name := range ungrouped {
+		sb.WriteString(formatPlantUMLEntity(name, d.Entities[name], opts))
+	}
+	for _, group := range groups {
+		sb.WriteString(fmt.Sprintf("package \"%s\" {\n", escapePlantUML(group.name)))
+		for _, name := range group.keys {
+			sb.WriteString(formatPlantUMLEntity(name, d.Entities[name], opts))
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	// Write relationships
+	for _, rel := range d.Relationships {
+		sb.WriteString(formatPlantUMLRelationship(rel, opts))
+	}
+
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+// formatPlantUMLEntity formats an entity as a PlantUML entity block,
+// followed by any entity and attribute notes. The entity is identified by its key.
+func formatPlantUMLEntity(name string, entity *Entity, opts RenderOptions) string {
+	var sb strings.Builder
+
+	alias := sanitizeName(name)
+	sb.WriteString(fmt.Sprintf("entity \"%s\" as %s {\n",
+		escapePlantUML(name),
+		alias))
+
+	// Primary keys are listed above the separator, everything else below
+	visible := opts.attributes(entity)
+	var keys, attrs []*Attribute
+	for _, attr := range visible {
+		if hasKey(entity, attr, PrimaryKey) {
+			keys = append(keys, attr)
+		} else {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	for _, attr := range keys {
+		sb.WriteString(formatPlantUMLAttribute(attr, attributeKeys(entity, attr), opts))
+	}
+	if !opts.HideAttributes {
+		sb.WriteString("  --\n")
+	}
+	for _, attr := range attrs {
+		sb.WriteString(formatPlantUMLAttribute(attr, attributeKeys(entity, attr), opts))
+	}
+
+	sb.WriteString("}\n")
+
+	if entity.Note != nil && !opts.HideNotes {
+		sb.WriteString(formatPlantUMLNote("top of "+alias, *entity.Note))
+	}
+	for _, attr := range visible {
+		if attr.Note != nil && !opts.HideNotes {
+			sb.WriteString(formatPlantUMLNote(fmt.Sprintf("right of %s::%s", alias, attr.Name), *attr.Note))
+		}
+	}
+
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// formatPlantUMLAttribute formats an attribute and its keys for PlantUML
+// syntax.
+func formatPlantUMLAttribute(attr *Attribute, keys []KeyType, opts RenderOptions) string {
+	var sb strings.Builder
+
+	sb.WriteString("  ")
+
+	// Mark primary keys
+	if slices.Contains(keys, PrimaryKey) {
+		sb.WriteString("* ")
+	}
+
+	sb.WriteString(attr.Name)
+	if !opts.HideTypes {
+		sb.WriteString(" : " + opts.typeName(attr.Type))
+	}
+
+	// Add nullable indicator
+	if attr.Nullable {
+		sb.WriteString("?")
+	}
+
+	// Add stereotypes for foreign and unique keys
+	for _, kt := range keys {
+		if kt != PrimaryKey {
+			sb.WriteString(fmt.Sprintf(" <<%s>>", kt))
+		}
+	}
+
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// formatPlantUMLRelationship formats a relationship for PlantUML syntax.
+func formatPlantUMLRelationship(rel *Relationship, opts RenderOptions) string {
+	symbol := crowsFoot(rel.Participation())
+	label := rel.Field
+	if rel.Label != nil {
+		label = *rel.Label
+	}
+
+	line := fmt.Sprintf("%s %s %s : %s\n",
+		sanitizeName(rel.From),
+		symbol,
+		sanitizeName(rel.To),
+		escapePlantUML(label))
+
+	if rel.Note != nil && !opts.HideNotes {
+		line += formatPlantUMLNote("on link", *rel.Note)
+	}
+
+	return line
+}
+
+// formatPlantUMLNote formats a multi-line note attached to the given target.
+func formatPlantUMLNote(target, note string) string {
+	return fmt.Sprintf("note %s\n%s\nend note\n", target, note)
+}
+
+// escapePlantUML escapes special characters for single-line PlantUML text.
+func escapePlantUML(s string) string {
+	s = strings.ReplaceAll(s, "\"", "'")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	return s
+}
@@ -0,0 +1,134 @@
+package erd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToPlantUML(t *testing.T) {
+	diagram := NewDiagram("Shop").
+		AddEntity(NewEntity("User").
+			WithNote("Registered customer").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("Email", "string").WithUnique()).
+			AddAttribute(NewAttribute("Bio", "*string").WithNullable().WithNote("Free text"))).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("UserID", "string").WithForeignKey())).
+		AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany).WithLabel("places"))
+
+	output := diagram.ToPlantUML()
+
+	want := []string{
+		"@startuml\n",
+		"title Shop\n",
+		"entity \"User\" as User {\n",
+		"  * ID : string\n  --\n",
+		"  Email : string <<UK>>\n",
+		"  Bio : string?\n",
+		"  UserID : string <<FK>>\n",
+		"note top of User\nRegistered customer\nend note\n",
+		"note right of User::Bio\nFree text\nend note\n",
+		"User ||--o{ Order : places\n",
+		"@enduml\n",
+	}
+	for _, w := range want {
+		if !strings.Contains(output, w) {
+			t.Errorf("ToPlantUML() should contain %q, got:\n%s", w, output)
+		}
+	}
+
+	// Entities are ordered by name, matching ToMermaid
+	if strings.Index(output, "as Order") > strings.Index(output, "as User") {
+		t.Errorf("ToPlantUML() should order entities by name, got:\n%s", output)
+	}
+}
+
+func TestToPlantUML_WithEmptyTitle(t *testing.T) {
+	diagram := &Diagram{
+		Title:    "",
+		Entities: make(map[string]*Entity),
+	}
+	diagram.AddEntity(NewEntity("User").AddAttribute(NewAttribute("ID", "string")))
+
+	output := diagram.ToPlantUML()
+
+	if strings.Contains(output, "title") {
+		t.Errorf("ToPlantUML() should not include title for empty title, got:\n%s", output)
+	}
+}
+
+func TestPlantUMLCardinality(t *testing.T) {
+	tests := []struct {
+		name        string
+		cardinality Cardinality
+		want        string
+	}{
+		{"one to one", OneToOne, "||--||"},
+		{"one to many", OneToMany, "||--o{"},
+		{"many to one", ManyToOne, "}o--||"},
+		{"many to many", ManyToMany, "}o--o{"},
+		{"default/unknown", Cardinality("unknown"), "||--||"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rel := NewRelationship("User", "Post", "Posts", tt.cardinality)
+			if got := crowsFoot(rel.Participation()); got != tt.want {
+				t.Errorf("crowsFoot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatPlantUMLRelationship_WithNote(t *testing.T) {
+	rel := NewRelationship("User", "Post", "Posts", OneToMany).WithNote("cascade delete")
+	output := formatPlantUMLRelationship(rel, RenderOptions{})
+
+	if !strings.Contains(output, "User ||--o{ Post : Posts\n") {
+		t.Errorf("formatPlantUMLRelationship() should fall back to field label, got %q", output)
+	}
+	if !strings.Contains(output, "note on link\ncascade delete\nend note\n") {
+		t.Errorf("formatPlantUMLRelationship() should contain link note, got %q", output)
+	}
+}
+
+func TestFormatPlantUMLRelationship_Participation(t *testing.T) {
+	rel := NewRelationship("User", "Profile", "Profile", OneToOne).WithToParticipation(ZeroOrOne)
+	if got, want := formatPlantUMLRelationship(rel, RenderOptions{}), "User ||--o| Profile : Profile\n"; got != want {
+		t.Errorf("formatPlantUMLRelationship() = %q, want %q", got, want)
+	}
+}
+
+func TestEscapePlantUML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"quote", `test"value`, "test'value"},
+		{"newline", "test\nvalue", "test\\nvalue"},
+		{"no special chars", "test value", "test value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapePlantUML(tt.input); got != tt.want {
+				t.Errorf("escapePlantUML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToPlantUML_CompositeKeys(t *testing.T) {
+	output := testCompositeDiagram().ToPlantUML()
+
+	for _, exp := range []string{
+		"entity \"LineItem\" as LineItem {\n  * TenantID : string <<FK>>\n  * OrderID : string <<FK>>\n  * SKU : string\n  --\n}\n",
+		"  * TenantID : string <<FK>> <<UK>>\n  * ID : string\n  --\n  Number : string <<UK>>\n",
+	} {
+		if !strings.Contains(output, exp) {
+			t.Errorf("ToPlantUML() should contain %q, got:\n%s", exp, output)
+		}
+	}
+}
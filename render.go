@@ -0,0 +1,269 @@
+package erd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Format identifies a diagram output format.
+type Format string
+
+// Format constants.
+const (
+	MermaidFormat  Format = "mermaid"
+	DOTFormat      Format = "dot"
+	PlantUMLFormat Format = "plantuml"
+	DBMLFormat     Format = "dbml"
+	JSONFormat     Format = "json"
+	SQLFormat      Format = "sql"
+)
+
+// Renderer writes a diagram in some output format.
+type Renderer interface {
+	Render(w io.Writer, d *Diagram, opts RenderOptions) error
+}
+
+// RendererFunc adapts a function to the [Renderer] interface.
+type RendererFunc func(w io.Writer, d *Diagram, opts RenderOptions) error
+
+// Render calls f(w, d, opts).
+func (f RendererFunc) Render(w io.Writer, d *Diagram, opts RenderOptions) error {
+	return f(w, d, opts)
+}
+
+// renderers is the format registry, seeded with the built-in formats.
+var renderers = struct {
+	byFormat map[Format]Renderer
+	sync.RWMutex
+}{
+	byFormat: map[Format]Renderer{
+		MermaidFormat:  RendererFunc(writeMermaid),
+		DOTFormat:      RendererFunc(writeDOT),
+		PlantUMLFormat: stringRenderer((*Diagram).ToPlantUMLWith),
+		DBMLFormat:     stringRenderer((*Diagram).ToDBMLWith),
+		JSONFormat: RendererFunc(func(w io.Writer, d *Diagram, _ RenderOptions) error {
+			return d.WriteJSON(w)
+		}),
+		SQLFormat: RendererFunc(writeSQL),
+	},
+}
+
+// writeMermaid and writeDOT adapt the streaming renderers to [RendererFunc].
+func writeMermaid(w io.Writer, d *Diagram, opts RenderOptions) error {
+	return d.WriteMermaidWith(w, opts)
+}
+
+func writeDOT(w io.Writer, d *Diagram, opts RenderOptions) error {
+	return d.WriteDOTWith(w, opts)
+}
+
+// writeSQL adapts [Diagram.ToSQL] to [RendererFunc], in the options' dialect.
+func writeSQL(w io.Writer, d *Diagram, opts RenderOptions) error {
+	ddl, err := d.ToSQL(opts.dialect())
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, ddl)
+	return err
+}
+
+// stringRenderer adapts a To*With method to the [Renderer] interface.
+func stringRenderer(render func(*Diagram, RenderOptions) string) Renderer {
+	return RendererFunc(func(w io.Writer, d *Diagram, opts RenderOptions) error {
+		_, err := io.WriteString(w, render(d, opts))
+		return err
+	})
+}
+
+// RegisterRenderer makes a renderer available to [Render] under the given
+// format name, replacing any renderer registered under that name, including
+// the built-in ones. It panics if r is nil.
+func RegisterRenderer(format Format, r Renderer) {
+	if r == nil {
+		panic("erd: RegisterRenderer renderer is nil")
+	}
+	renderers.Lock()
+	defer renderers.Unlock()
+	renderers.byFormat[format] = r
+}
+
+// Formats returns the registered format names in sorted order.
+func Formats() []Format {
+	renderers.RLock()
+	defer renderers.RUnlock()
+	formats := make([]Format, 0, len(renderers.byFormat))
+	for format := range renderers.byFormat {
+		formats = append(formats, format)
+	}
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i] < formats[j]
+	})
+	return formats
+}
+
+// Render writes the diagram to w in the given format with default options.
+func Render(w io.Writer, d *Diagram, format Format) error {
+	return RenderWith(w, d, format, RenderOptions{})
+}
+
+// RenderWith writes the diagram to w in the given format using the options.
+// It returns an error if no renderer is registered for the format.
+func RenderWith(w io.Writer, d *Diagram, format Format, opts RenderOptions) error {
+	renderers.RLock()
+	r, ok := renderers.byFormat[format]
+	renderers.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown format %q", format)
+	}
+	return r.Render(w, d, opts)
+}
+
+// renderWriter wraps the writer of a streaming renderer. It keeps the first
+// write error and drops later writes, so renderers check the error once.
+type renderWriter struct {
+	w   io.Writer
+	err error
+}
+
+// WriteString writes s unless an earlier write failed.
+func (rw *renderWriter) WriteString(s string) {
+	if rw.err != nil {
+		return
+	}
+	_, rw.err = io.WriteString(rw.w, s)
+}
+
+// RankDir is the direction GraphViz lays out a DOT diagram in.
+type RankDir string
+
+// RankDir constants.
+const (
+	LeftToRight RankDir = "LR"
+	TopToBottom RankDir = "TB"
+	RightToLeft RankDir = "RL"
+	BottomToTop RankDir = "BT"
+)
+
+// RenderOptions controls how diagrams are rendered. The zero value renders
+// the same output as the plain To* methods.
+type RenderOptions struct {
+	// RankDir sets the DOT layout direction. Empty or unknown values use
+	// LeftToRight.
+	RankDir RankDir
+
+	// Dialect sets the SQL dialect for [SQLFormat]. Empty uses PostgreSQL.
+	// The other options do not apply to SQL.
+	Dialect Dialect
+
+	// HTMLTables draws DOT entities as HTML-like tables with a port per
+	// attribute, so relationships recording the attributes they join connect
+	// those rows rather than whole entities. Primary key rows are bold and
+	// nullable rows italic.
+	HTMLTables bool
+
+	// GroupByPackage groups entities by [Entity.Package]: DOT clusters,
+	// PlantUML packages and DBML table groups. Mermaid has no grouping
+	// construct, so entities are ordered by package under a comment per
+	// package instead. Entities without a package are not grouped.
+	GroupByPackage bool
+
+	// HideAttributes draws entities without attributes, for overviews.
+	HideAttributes bool
+
+	// KeysOnly draws only primary, foreign and unique key attributes.
+	// DBML ignores this and HideAttributes, since its refs join columns.
+	KeysOnly bool
+
+	// HideTypes omits attribute types in DOT and PlantUML. Mermaid and DBML
+	// require types and always show them.
+	HideTypes bool
+
+	// FullTypes shows types with their package qualifiers, such as
+	// time.Time and *string, instead of shortening them to Time and string.
+	// Mermaid cannot draw dots or pointers in types, so it writes time_Time.
+	FullTypes bool
+
+	// HideNotes omits entity, attribute and relationship notes.
+	HideNotes bool
+
+	// HideTitle omits the diagram title. Mermaid diagrams have no title.
+	HideTitle bool
+}
+
+// rankDir returns the DOT layout direction.
+func (o RenderOptions) rankDir() RankDir {
+	switch o.RankDir {
+	case TopToBottom, RightToLeft, BottomToTop:
+		return o.RankDir
+	default:
+		return LeftToRight
+	}
+}
+
+// dialect returns the SQL dialect.
+func (o RenderOptions) dialect() Dialect {
+	if o.Dialect == "" {
+		return PostgreSQL
+	}
+	return o.Dialect
+}
+
+// attributes returns the attributes of an entity to draw.
+func (o RenderOptions) attributes(entity *Entity) []*Attribute {
+	if o.HideAttributes {
+		return nil
+	}
+	if !o.KeysOnly {
+		return entity.Attributes
+	}
+	var keys []*Attribute
+	for _, attr := range entity.Attributes {
+		if len(attributeKeys(entity, attr)) > 0 {
+			keys = append(keys, attr)
+		}
+	}
+	return keys
+}
+
+// typeName returns an attribute type as drawn.
+func (o RenderOptions) typeName(attrType string) string {
+	if o.FullTypes {
+		return attrType
+	}
+	return sanitizeType(attrType)
+}
+
+// packageGroup is a set of entities sharing a package.
+type packageGroup struct {
+	name string
+	keys []string
+}
+
+// entityGroups returns the entity keys to render: ungrouped entities
+// first, then one group per package in package order. Without grouping
+// every entity is ungrouped.
+func (d *Diagram) entityGroups(opts RenderOptions) (ungrouped []string, groups []packageGroup) {
+	if !opts.GroupByPackage {
+		return d.entityNames(), nil
+	}
+
+	byPackage := make(map[string][]string)
+	for _, key := range d.entityNames() {
+		entity := d.Entities[key]
+		if entity.Package == nil || *entity.Package == "" {
+			ungrouped = append(ungrouped, key)
+			continue
+		}
+		byPackage[*entity.Package] = append(byPackage[*entity.Package], key)
+	}
+
+	for pkg, keys := range byPackage {
+		groups = append(groups, packageGroup{name: pkg, keys: keys})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].name < groups[j].name
+	})
+	return ungrouped, groups
+}
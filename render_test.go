@@ -0,0 +1,364 @@
+package erd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testPackagedDiagram spreads entities over two packages and none.
+func testPackagedDiagram() *Diagram {
+	return NewDiagram("Shop").
+		AddEntity(NewEntity("User").
+			WithPackage("github.com/shop/auth").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Session").
+			WithPackage("github.com/shop/auth").
+			AddAttribute(NewAttribute("Token", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Order").
+			WithPackage("github.com/shop/billing").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("UserID", "string").WithForeignKey())).
+		AddEntity(NewEntity("Tag").
+			AddAttribute(NewAttribute("Name", "string"))).
+		AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany))
+}
+
+func TestRenderOptions_GroupByPackage(t *testing.T) {
+	d := testPackagedDiagram()
+	opts := RenderOptions{GroupByPackage: true}
+
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{
+			name:   "dot",
+			output: d.ToDOTWith(opts),
+			expected: []string{
+				"    Tag [label=\"{Tag|Name: string\\l}\"];\n",
+				"    subgraph cluster_github_com_shop_auth {\n" +
+					"        label=\"github.com/shop/auth\";\n" +
+					"        Session [label=\"{Session|PK Token: string\\l}\"];\n" +
+					"        User [label=\"{User|PK ID: string\\l}\"];\n" +
+					"    }\n",
+				"    subgraph cluster_github_com_shop_billing {\n",
+				"    User -> Order [",
+			},
+		},
+		{
+			name:   "mermaid",
+			output: d.ToMermaidWith(opts),
+			expected: []string{
+				"erDiagram\n    Tag {\n",
+				"    %% package github.com/shop/auth\n    Session {\n",
+				"    %% package github.com/shop/billing\n    Order {\n",
+				"    User ||--o{ Order : Orders\n",
+			},
+		},
+		{
+			name:   "plantuml",
+			output: d.ToPlantUMLWith(opts),
+			expected: []string{
+				"entity \"Tag\" as Tag {\n",
+				"package \"github.com/shop/auth\" {\nentity \"Session\" as Session {\n",
+				"package \"github.com/shop/billing\" {\nentity \"Order\" as Order {\n",
+				"}\n\n}\n\nUser ||--o{ Order : Orders\n",
+			},
+		},
+		{
+			name:   "dbml",
+			output: d.ToDBMLWith(opts),
+			expected: []string{
+				"Table Tag {\n",
+				"TableGroup \"github.com/shop/auth\" {\n  Session\n  User\n}\n",
+				"TableGroup \"github.com/shop/billing\" {\n  Order\n}\n",
+				"Ref: User.ID < Order.UserID\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInOrder(t, tt.output, tt.expected)
+		})
+	}
+}
+
+func TestRenderOptions_GroupingOptIn(t *testing.T) {
+	d := testPackagedDiagram()
+
+	if dot := d.ToDOT(); strings.Contains(dot, "subgraph") {
+		t.Errorf("ToDOT() should not group by package, got:\n%s", dot)
+	}
+	if mermaid := d.ToMermaid(); strings.Contains(mermaid, "%% package") {
+		t.Errorf("ToMermaid() should not group by package, got:\n%s", mermaid)
+	}
+}
+
+// testOptionsDiagram has notes, qualified types and non-key attributes for
+// exercising render options.
+func testOptionsDiagram() *Diagram {
+	return NewDiagram("Shop").
+		AddEntity(NewEntity("User").
+			WithNote("Registered customer").
+			AddAttribute(NewAttribute("ID", "uuid.UUID").WithPrimaryKey()).
+			AddAttribute(NewAttribute("Email", "string").WithUnique().WithNote("Login")).
+			AddAttribute(NewAttribute("Meta", "map[string]interface{}")).
+			AddAttribute(NewAttribute("Created", "time.Time"))).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("UserID", "uuid.UUID").WithForeignKey())).
+		AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany).WithNote("Placed orders"))
+}
+
+func TestRenderOptions(t *testing.T) {
+	d := testOptionsDiagram()
+
+	tests := []struct {
+		name     string
+		render   func(RenderOptions) string
+		opts     RenderOptions
+		expected []string
+		absent   []string
+	}{
+		{
+			name:     "dot hide attributes",
+			render:   d.ToDOTWith,
+			opts:     RenderOptions{HideAttributes: true},
+			expected: []string{"    Order [label=\"{Order}\"];\n", "    User [label=\"{User}\"];\n"},
+			absent:   []string{"Email"},
+		},
+		{
+			name:     "dot keys only",
+			render:   d.ToDOTWith,
+			opts:     RenderOptions{KeysOnly: true},
+			expected: []string{`User [label="{User|PK ID: UUID\lUK Email: string\l}"];`},
+			absent:   []string{"Created", "Meta"},
+		},
+		{
+			name:     "dot hide types",
+			render:   d.ToDOTWith,
+			opts:     RenderOptions{HideTypes: true},
+			expected: []string{`User [label="{User|PK ID\lUK Email\lMeta\lCreated\l}"];`},
+		},
+		{
+			name:     "dot full types",
+			render:   d.ToDOTWith,
+			opts:     RenderOptions{FullTypes: true},
+			expected: []string{`PK ID: uuid.UUID\l`, `Meta: map[string]interface\{\}\l`, `Created: time.Time\l`},
+		},
+		{
+			name:     "dot rankdir and title",
+			render:   d.ToDOTWith,
+			opts:     RenderOptions{RankDir: TopToBottom, HideTitle: true},
+			expected: []string{"    rankdir=TB;\n"},
+			absent:   []string{"label=\"Shop\"", "labelloc"},
+		},
+		{
+			name:     "dot unknown rankdir",
+			render:   d.ToDOTWith,
+			opts:     RenderOptions{RankDir: "diagonal"},
+			expected: []string{"    rankdir=LR;\n"},
+		},
+		{
+			name:     "mermaid hide attributes",
+			render:   d.ToMermaidWith,
+			opts:     RenderOptions{HideAttributes: true},
+			expected: []string{"erDiagram\n    Order\n    User\n    User ||--o{ Order : Orders\n"},
+		},
+		{
+			name:     "mermaid keys only without notes",
+			render:   d.ToMermaidWith,
+			opts:     RenderOptions{KeysOnly: true, HideNotes: true},
+			expected: []string{"    User {\n        UUID ID PK\n        string Email UK\n    }\n"},
+			absent:   []string{"Login", "Created"},
+		},
+		{
+			name:     "mermaid full types",
+			render:   d.ToMermaidWith,
+			opts:     RenderOptions{FullTypes: true},
+			expected: []string{"        uuid_UUID ID PK\n", "        time_Time Created\n"},
+		},
+		{
+			name:     "plantuml options",
+			render:   d.ToPlantUMLWith,
+			opts:     RenderOptions{HideTypes: true, HideNotes: true, HideTitle: true},
+			expected: []string{"entity \"User\" as User {\n  * ID\n  --\n  Email <<UK>>\n"},
+			absent:   []string{"title Shop", "note ", "Placed orders"},
+		},
+		{
+			name:     "plantuml hide attributes",
+			render:   d.ToPlantUMLWith,
+			opts:     RenderOptions{HideAttributes: true},
+			expected: []string{"entity \"User\" as User {\n}\n"},
+		},
+		{
+			name:     "dbml options",
+			render:   d.ToDBMLWith,
+			opts:     RenderOptions{FullTypes: true, HideNotes: true, HideTitle: true, HideAttributes: true},
+			expected: []string{"Table User {\n  ID \"uuid.UUID\" [pk, not null]\n  Email string [unique, not null]\n"},
+			absent:   []string{"Project", "Note", "note:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := tt.render(tt.opts)
+			assertInOrder(t, output, tt.expected)
+			for _, s := range tt.absent {
+				if strings.Contains(output, s) {
+					t.Errorf("output should not contain %q, got:\n%s", s, output)
+				}
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	d := testOptionsDiagram()
+	postgres, err := d.ToSQL(PostgreSQL)
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+	mysql, err := d.ToSQL(MySQL)
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{MermaidFormat, d.ToMermaid()},
+		{DOTFormat, d.ToDOT()},
+		{PlantUMLFormat, d.ToPlantUML()},
+		{DBMLFormat, d.ToDBML()},
+		{SQLFormat, postgres},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var sb strings.Builder
+			if err := Render(&sb, d, tt.format); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if sb.String() != tt.want {
+				t.Errorf("Render() = %q, want %q", sb.String(), tt.want)
+			}
+		})
+	}
+
+	var sb strings.Builder
+	if err := RenderWith(&sb, d, DOTFormat, RenderOptions{HideAttributes: true}); err != nil {
+		t.Fatalf("RenderWith() error = %v", err)
+	}
+	if !strings.Contains(sb.String(), `User [label="{User}"];`) {
+		t.Errorf("RenderWith() should apply options, got:\n%s", sb.String())
+	}
+
+	sb.Reset()
+	if err := RenderWith(&sb, d, SQLFormat, RenderOptions{Dialect: MySQL}); err != nil {
+		t.Fatalf("RenderWith(sql) error = %v", err)
+	}
+	if sb.String() != mysql {
+		t.Errorf("RenderWith(sql) should use the dialect, got:\n%s", sb.String())
+	}
+
+	sb.Reset()
+	if err := Render(&sb, d, JSONFormat); err != nil {
+		t.Fatalf("Render(json) error = %v", err)
+	}
+	if !strings.Contains(sb.String(), `"title": "Shop"`) {
+		t.Errorf("Render(json) should write JSON, got:\n%s", sb.String())
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	d := testOptionsDiagram()
+
+	if err := Render(&strings.Builder{}, d, "svg"); err == nil || err.Error() != `unknown format "svg"` {
+		t.Errorf("Render() error = %v, want unknown format", err)
+	}
+	if err := Render(failingWriter{}, d, MermaidFormat); err == nil {
+		t.Error("Render() should return the writer error")
+	}
+	if err := RenderWith(&strings.Builder{}, d, SQLFormat, RenderOptions{Dialect: "oracle"}); err == nil {
+		t.Error("RenderWith(sql) should reject an unknown dialect")
+	}
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	const format Format = "names"
+	RegisterRenderer(format, RendererFunc(func(w io.Writer, d *Diagram, opts RenderOptions) error {
+		for _, name := range d.entityNames() {
+			if _, err := fmt.Fprintln(w, name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	t.Cleanup(func() {
+		renderers.Lock()
+		delete(renderers.byFormat, format)
+		renderers.Unlock()
+	})
+
+	var sb strings.Builder
+	if err := Render(&sb, testOptionsDiagram(), format); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if sb.String() != "Order\nUser\n" {
+		t.Errorf("Render() = %q, want %q", sb.String(), "Order\nUser\n")
+	}
+
+	if got := Formats(); !reflect.DeepEqual(got, []Format{DBMLFormat, DOTFormat, JSONFormat, MermaidFormat, format, PlantUMLFormat, SQLFormat}) {
+		t.Errorf("Formats() = %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterRenderer(nil) should panic")
+		}
+	}()
+	RegisterRenderer("nil", nil)
+}
+
+// benchmarkDiagram builds a warehouse-sized diagram of n entities, each
+// related to the next.
+func benchmarkDiagram(n int) *Diagram {
+	d := NewDiagram("Warehouse")
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("Table%04d", i)
+		entity := NewEntity(name).
+			WithNote("Generated table").
+			AddAttribute(NewAttribute("ID", "int64").WithPrimaryKey()).
+			AddAttribute(NewAttribute("NextID", "int64").WithForeignKey()).
+			AddAttribute(NewAttribute("Name", "string").WithUnique()).
+			AddAttribute(NewAttribute("Amount", "float64")).
+			AddAttribute(NewAttribute("Deleted", "*time.Time").WithNullable())
+		d.AddEntity(entity)
+		if i > 0 {
+			d.AddRelationship(NewRelationship(fmt.Sprintf("Table%04d", i-1), name, "Next", OneToOne))
+		}
+	}
+	return d
+}
+
+// limitedWriter fails once more than n writes have been made.
+type limitedWriter struct {
+	written strings.Builder
+	n       int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errors.New("write failed")
+	}
+	w.n--
+	return w.written.Write(p)
+}
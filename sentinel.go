@@ -1,6 +1,8 @@
 package erd
 
 import (
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/zoobzio/sentinel"
@@ -10,24 +12,71 @@ func init() {
 	sentinel.Tag("erd")
 }
 
+// SchemaOptions configures how [FromSchemaWith] builds a diagram.
+type SchemaOptions struct {
+	// InferForeignKeys links attributes named after another entity's primary
+	// key, such as UserID or user_id for User.ID, to that key with a foreign
+	// key group.
+	InferForeignKeys bool
+}
+
 // FromSchema converts a sentinel schema to an ERD diagram.
 // The schema is typically obtained via sentinel.Schema() after scanning types.
 func FromSchema(title string, schema map[string]sentinel.Metadata) *Diagram {
+	return FromSchemaWith(title, schema, SchemaOptions{})
+}
+
+// FromSchemaWith converts a sentinel schema to an ERD diagram using the given
+// options.
+//
+// Attributes tagged fk:Entity or fk:Entity.Attribute are linked to the
+// relationship between their entity and the referenced one: the relationship
+// records the attributes it joins, and is added as many-to-one (one-to-one
+// for unique keys) when the types have no field relating them.
+func FromSchemaWith(title string, schema map[string]sentinel.Metadata, opts SchemaOptions) *Diagram {
 	diagram := NewDiagram(title)
 
-	// Add entities, filtering out relationship fields
-	for _, meta := range schema {
+	// Visit types in name order so relationships are emitted deterministically
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Add entities, filtering out relationship fields. Types sharing a name
+	// across packages are keyed by qualified name.
+	entities := make(map[*Entity]string, len(schema))
+	for _, key := range keys {
+		meta := schema[key]
 		entity := fromMetadataFiltered(meta)
 		diagram.AddEntity(entity)
+		entities[entity] = meta.FQDN
+	}
+	names := make(map[string]string, len(schema))
+	for name, entity := range diagram.Entities {
+		names[entities[entity]] = name
 	}
 
-	// Add relationships
-	for _, meta := range schema {
-		for _, rel := range meta.Relationships {
-			diagram.AddRelationship(relationshipFromSentinel(rel))
+	// Add relationships, pointing fully qualified type names at entity names
+	for _, key := range keys {
+		kinds := make(map[string]sentinel.FieldKind, len(schema[key].Fields))
+		for _, field := range schema[key].Fields {
+			kinds[field.Name] = field.Kind
+		}
+		for _, rel := range schema[key].Relationships {
+			r := relationshipFromSentinel(rel, kinds[rel.Field])
+			if name, ok := names[r.From]; ok {
+				r.From = name
+			}
+			if name, ok := names[r.To]; ok {
+				r.To = name
+			}
+			diagram.AddRelationship(r)
 		}
 	}
 
+	diagram.linkForeignKeys(opts.InferForeignKeys)
+
 	return diagram
 }
 
@@ -51,8 +100,11 @@ func fromMetadataFiltered(meta sentinel.Metadata) *Entity {
 		if relFields[field.Name] {
 			continue
 		}
-		attr := attributeFromField(field)
+		attr, keys := attributeFromField(field)
 		entity.AddAttribute(attr)
+		for _, key := range keys {
+			addTagKey(entity, key)
+		}
 	}
 
 	return entity
@@ -67,15 +119,19 @@ func FromMetadata(meta sentinel.Metadata) *Entity {
 	}
 
 	for _, field := range meta.Fields {
-		attr := attributeFromField(field)
+		attr, keys := attributeFromField(field)
 		entity.AddAttribute(attr)
+		for _, key := range keys {
+			addTagKey(entity, key)
+		}
 	}
 
 	return entity
 }
 
-// attributeFromField converts a sentinel FieldMetadata to an ERD Attribute.
-func attributeFromField(field sentinel.FieldMetadata) *Attribute {
+// attributeFromField converts a sentinel FieldMetadata to an ERD Attribute,
+// along with the key groups its erd tag adds the attribute to.
+func attributeFromField(field sentinel.FieldMetadata) (*Attribute, []*Key) {
 	attrType := field.Type
 
 	// Detect nullability from pointer types
@@ -91,37 +147,139 @@ func attributeFromField(field sentinel.FieldMetadata) *Attribute {
 	}
 
 	// Parse erd tag for key types and notes
+	var keys []*Key
 	if erdTag, ok := field.Tags["erd"]; ok {
-		parseErdTag(attr, erdTag)
+		keys = parseErdTag(attr, erdTag)
 	}
 
-	return attr
+	return attr, keys
 }
 
 // parseErdTag parses the erd struct tag and applies settings to the attribute.
-// Supported values: pk, fk, uk, note:...
-func parseErdTag(attr *Attribute, tag string) {
+// Supported values: pk, fk, uk, fk:Entity, fk:Entity.Attribute,
+// fk:Entity.Attribute:name, uk:name and note:...
+//
+// The first of pk, fk and uk sets the attribute's key. Further keys, foreign
+// keys naming a reference and named unique keys are returned as single
+// attribute key groups for [addTagKey] to merge into the entity.
+func parseErdTag(attr *Attribute, tag string) []*Key {
+	var keys []*Key
+	plain := func(keyType KeyType) {
+		if attr.Key == nil {
+			attr.WithKey(keyType)
+			return
+		}
+		if *attr.Key != keyType {
+			keys = append(keys, NewKey(tagKeyName(keyType, attr.Name), keyType, attr.Name))
+		}
+	}
+
 	parts := strings.Split(tag, ",")
 	for _, part := range parts {
 		part = strings.TrimSpace(part)
 		switch {
 		case part == "pk":
-			attr.WithPrimaryKey()
+			plain(PrimaryKey)
 		case part == "fk":
-			attr.WithForeignKey()
+			plain(ForeignKey)
 		case part == "uk":
-			attr.WithUnique()
+			plain(UniqueKey)
+		case strings.HasPrefix(part, "fk:"):
+			ref, name := strings.TrimPrefix(part, "fk:"), tagKeyName(ForeignKey, attr.Name)
+			if i := strings.LastIndex(ref, ":"); i >= 0 {
+				ref, name = ref[:i], ref[i+1:]
+			}
+			key := NewKey(name, ForeignKey, attr.Name)
+			if i := strings.LastIndex(ref, "."); i > 0 {
+				key.WithReference(ref[:i], ref[i+1:])
+			} else {
+				key.WithReference(ref)
+			}
+			keys = append(keys, key)
+		case strings.HasPrefix(part, "uk:"):
+			keys = append(keys, NewKey(strings.TrimPrefix(part, "uk:"), UniqueKey, attr.Name))
 		case strings.HasPrefix(part, "note:"):
 			note := strings.TrimPrefix(part, "note:")
 			attr.WithNote(note)
 		}
 	}
+
+	return keys
+}
+
+// tagKeyName names a key group declared by an erd tag on one attribute.
+func tagKeyName(keyType KeyType, attr string) string {
+	if keyType == PrimaryKey {
+		return "pk"
+	}
+	return strings.ToLower(string(keyType)) + "_" + attr
+}
+
+// addTagKey merges a key group parsed from an erd tag into the entity.
+// Primary keys join the entity's primary key group and unique keys the group
+// of the same name. A foreign key joins an earlier one of the same name
+// referencing other attributes of the same entity, forming a composite
+// foreign key; unnamed foreign keys are named after their attribute, so
+// each stays a key of its own.
+func addTagKey(entity *Entity, key *Key) {
+	for _, existing := range entity.Keys {
+		if existing.Type != key.Type || slices.Contains(existing.Attributes, key.Attributes[0]) {
+			continue
+		}
+		switch {
+		case key.Type == PrimaryKey,
+			key.Type == UniqueKey && existing.Name == key.Name,
+			key.Type == ForeignKey && sameKeyTarget(existing, key):
+			existing.Attributes = append(existing.Attributes, key.Attributes...)
+			if key.Reference != nil {
+				existing.Reference.Attributes = append(existing.Reference.Attributes, key.Reference.Attributes...)
+			}
+			return
+		}
+	}
+	entity.AddKey(key)
+}
+
+// sameKeyTarget reports whether a foreign key parsed from a tag extends an
+// earlier one: both share a name and an attribute of the same entity, and
+// the earlier key does not already reference that attribute.
+func sameKeyTarget(existing, key *Key) bool {
+	if existing.Name != key.Name || existing.Reference == nil || key.Reference == nil {
+		return false
+	}
+	if len(existing.Reference.Attributes) == 0 || len(key.Reference.Attributes) == 0 {
+		return false
+	}
+	return existing.Reference.Entity == key.Reference.Entity &&
+		!slices.Contains(existing.Reference.Attributes, key.Reference.Attributes[0])
 }
 
-// relationshipFromSentinel converts a sentinel TypeRelationship to an ERD Relationship.
-func relationshipFromSentinel(rel sentinel.TypeRelationship) *Relationship {
+// relationshipFromSentinel converts a sentinel TypeRelationship to an ERD
+// Relationship. The kind of the field holding it sets how many target
+// entities take part.
+func relationshipFromSentinel(rel sentinel.TypeRelationship, kind sentinel.FieldKind) *Relationship {
 	cardinality := cardinalityFromKind(rel.Kind)
-	return NewRelationship(rel.From, rel.To, rel.Field, cardinality)
+	r := NewRelationship(rel.From, rel.To, rel.Field, cardinality)
+	if p, ok := participationFromKind(kind); ok {
+		r.WithToParticipation(p)
+	}
+	return r
+}
+
+// participationFromKind maps a field kind to the participation of the
+// entities it holds: a pointer may be nil, a struct value is always present
+// and a collection may be empty.
+func participationFromKind(kind sentinel.FieldKind) (Participation, bool) {
+	switch kind {
+	case sentinel.KindPointer:
+		return ZeroOrOne, true
+	case sentinel.KindStruct:
+		return ExactlyOne, true
+	case sentinel.KindSlice, sentinel.KindMap:
+		return ZeroOrMore, true
+	default:
+		return "", false
+	}
 }
 
 // cardinalityFromKind maps sentinel relationship kinds to ERD cardinalities.
@@ -1,6 +1,8 @@
 package erd
 
 import (
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/zoobzio/sentinel"
@@ -103,6 +105,10 @@ func TestFromSchema(t *testing.T) {
 	if profileRel.Cardinality != OneToOne {
 		t.Errorf("expected Profile relationship to be OneToOne, got %s", profileRel.Cardinality)
 	}
+	// A pointer field may be nil
+	if _, to := profileRel.Participation(); to != ZeroOrOne {
+		t.Errorf("expected Profile relationship to reach zero or one Profile, got %s", to)
+	}
 
 	// Check Orders relationship is OneToMany
 	var ordersRel *Relationship
@@ -118,6 +124,14 @@ func TestFromSchema(t *testing.T) {
 	if ordersRel.Cardinality != OneToMany {
 		t.Errorf("expected Orders relationship to be OneToMany, got %s", ordersRel.Cardinality)
 	}
+
+	// Relationships point at the diagram's entities
+	if ordersRel.From != "User" || ordersRel.To != "Order" {
+		t.Errorf("expected Orders relationship User -> Order, got %s -> %s", ordersRel.From, ordersRel.To)
+	}
+	if errs := diagram.Validate(); len(errs) > 0 {
+		t.Errorf("Validate() unexpected errors: %v", errs)
+	}
 }
 
 func TestFromMetadata(t *testing.T) {
@@ -194,6 +208,107 @@ func TestParseErdTag(t *testing.T) {
 	}
 }
 
+func TestParseErdTag_Keys(t *testing.T) {
+	tests := []struct {
+		wantKey  *KeyType
+		tag      string
+		wantKeys []string
+	}{
+		{ptr(PrimaryKey), "pk,fk", []string{"fk_test FK [test]"}},
+		{ptr(PrimaryKey), "pk,pk", nil},
+		{ptr(PrimaryKey), "pk,fk:User.ID", []string{"fk_test FK [test] -> User[ID]"}},
+		{nil, "fk:User", []string{"fk_test FK [test] -> User[]"}},
+		{nil, "fk:auth.User.ID", []string{"fk_test FK [test] -> auth.User[ID]"}},
+		{nil, "fk:Order.ID:order", []string{"order FK [test] -> Order[ID]"}},
+		{ptr(UniqueKey), "uk,uk:email_tenant", []string{"email_tenant UK [test]"}},
+	}
+
+	for _, tt := range tests {
+		attr := NewAttribute("test", "string")
+		keys := parseErdTag(attr, tt.tag)
+
+		if tt.wantKey == nil && attr.Key != nil {
+			t.Errorf("tag %q: expected no key, got %v", tt.tag, *attr.Key)
+		} else if tt.wantKey != nil && (attr.Key == nil || *attr.Key != *tt.wantKey) {
+			t.Errorf("tag %q: expected key %v, got %v", tt.tag, *tt.wantKey, attr.Key)
+		}
+
+		var got []string
+		for _, key := range keys {
+			got = append(got, formatTestKey(key))
+		}
+		if !slices.Equal(got, tt.wantKeys) {
+			t.Errorf("tag %q: expected keys %v, got %v", tt.tag, tt.wantKeys, got)
+		}
+	}
+}
+
+func TestAddTagKey(t *testing.T) {
+	entity := NewEntity("LineItem").
+		AddAttribute(NewAttribute("TenantID", "string")).
+		AddAttribute(NewAttribute("OrderID", "string")).
+		AddAttribute(NewAttribute("Number", "int"))
+	tags := map[string]string{
+		"TenantID": "pk,fk:Order.TenantID:order,uk:number",
+		"OrderID":  "fk:Order.ID:order",
+		"Number":   "pk,uk:number",
+	}
+	for _, attr := range entity.Attributes {
+		for _, key := range parseErdTag(attr, tags[attr.Name]) {
+			addTagKey(entity, key)
+		}
+	}
+
+	var got []string
+	for _, key := range entity.Keys {
+		got = append(got, formatTestKey(key))
+	}
+	want := []string{
+		"order FK [TenantID OrderID] -> Order[TenantID ID]",
+		"number UK [TenantID Number]",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected keys %v, got %v", want, got)
+	}
+}
+
+func TestAddTagKey_SeparateForeignKeys(t *testing.T) {
+	// Unnamed foreign keys to the same entity stay one key per tag
+	entity := NewEntity("Session").
+		AddAttribute(NewAttribute("UserID", "string")).
+		AddAttribute(NewAttribute("UserEmail", "string"))
+	tags := map[string]string{
+		"UserID":    "fk:User.ID",
+		"UserEmail": "fk:User.Email",
+	}
+	for _, attr := range entity.Attributes {
+		for _, key := range parseErdTag(attr, tags[attr.Name]) {
+			addTagKey(entity, key)
+		}
+	}
+
+	var got []string
+	for _, key := range entity.Keys {
+		got = append(got, formatTestKey(key))
+	}
+	want := []string{
+		"fk_UserID FK [UserID] -> User[ID]",
+		"fk_UserEmail FK [UserEmail] -> User[Email]",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected keys %v, got %v", want, got)
+	}
+}
+
+// formatTestKey summarizes a key group for comparison.
+func formatTestKey(key *Key) string {
+	s := fmt.Sprintf("%s %s %v", key.Name, key.Type, key.Attributes)
+	if key.Reference != nil {
+		s += fmt.Sprintf(" -> %s%v", key.Reference.Entity, key.Reference.Attributes)
+	}
+	return s
+}
+
 func TestCardinalityFromKind(t *testing.T) {
 	// Scan Company to exercise embedding and map relationships
 	sentinel.Scan[Company]()
@@ -213,7 +328,7 @@ func TestCardinalityFromKind(t *testing.T) {
 	// Check for embedding relationship (Address)
 	var embeddingRel *Relationship
 	for _, rel := range diagram.Relationships {
-		if rel.From == "github.com/zoobzio/erd.Company" && rel.To == "github.com/zoobzio/erd.Address" {
+		if rel.From == "Company" && rel.To == "Address" {
 			embeddingRel = rel
 			break
 		}
@@ -224,6 +339,10 @@ func TestCardinalityFromKind(t *testing.T) {
 	if embeddingRel.Cardinality != OneToOne {
 		t.Errorf("expected embedding to be OneToOne, got %s", embeddingRel.Cardinality)
 	}
+	// An embedded value is always present
+	if _, to := embeddingRel.Participation(); to != ExactlyOne {
+		t.Errorf("expected embedding to reach exactly one Address, got %s", to)
+	}
 
 	// Check for map relationship (Employees)
 	var mapRel *Relationship
@@ -241,6 +360,29 @@ func TestCardinalityFromKind(t *testing.T) {
 	}
 }
 
+func TestParticipationFromKind(t *testing.T) {
+	tests := []struct {
+		kind   sentinel.FieldKind
+		want   Participation
+		wantOK bool
+	}{
+		{sentinel.KindPointer, ZeroOrOne, true},
+		{sentinel.KindStruct, ExactlyOne, true},
+		{sentinel.KindSlice, ZeroOrMore, true},
+		{sentinel.KindMap, ZeroOrMore, true},
+		{sentinel.KindScalar, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			got, ok := participationFromKind(tt.kind)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("participationFromKind(%q) = %q, %v, want %q, %v", tt.kind, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
 func TestCardinalityFromKindAllCases(t *testing.T) {
 	tests := []struct {
 		kind string
@@ -0,0 +1,592 @@
+package erd
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Dialect identifies the SQL flavor used for DDL generation.
+type Dialect string
+
+// Dialect constants.
+const (
+	PostgreSQL Dialect = "postgres"
+	MySQL      Dialect = "mysql"
+	SQLite     Dialect = "sqlite"
+)
+
+// sqlTable is the intermediate representation of a table used when
+// generating or parsing DDL. Schema is only set for parsed tables.
+type sqlTable struct {
+	Note        *string
+	Schema      string
+	Name        string
+	Columns     []sqlColumn
+	PrimaryKey  []string
+	Unique      [][]string
+	ForeignKeys []sqlForeignKey
+}
+
+// sqlColumn is a single column of a sqlTable.
+type sqlColumn struct {
+	Note     *string
+	Name     string
+	Type     string
+	Nullable bool
+}
+
+// sqlForeignKey is a FOREIGN KEY constraint of a sqlTable.
+type sqlForeignKey struct {
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
+// sqlType holds the column type for a Go type in each dialect.
+type sqlType struct {
+	Postgres string
+	MySQL    string
+	SQLite   string
+}
+
+// sqlTypes maps Go type names to dialect column types.
+var sqlTypes = map[string]sqlType{
+	"string":          {"TEXT", "VARCHAR(255)", "TEXT"},
+	"bool":            {"BOOLEAN", "BOOLEAN", "BOOLEAN"},
+	"int":             {"BIGINT", "BIGINT", "INTEGER"},
+	"int64":           {"BIGINT", "BIGINT", "INTEGER"},
+	"uint":            {"BIGINT", "BIGINT UNSIGNED", "INTEGER"},
+	"uint64":          {"BIGINT", "BIGINT UNSIGNED", "INTEGER"},
+	"int32":           {"INTEGER", "INT", "INTEGER"},
+	"uint32":          {"BIGINT", "INT UNSIGNED", "INTEGER"},
+	"rune":            {"INTEGER", "INT", "INTEGER"},
+	"int16":           {"SMALLINT", "SMALLINT", "INTEGER"},
+	"uint16":          {"INTEGER", "SMALLINT UNSIGNED", "INTEGER"},
+	"int8":            {"SMALLINT", "TINYINT", "INTEGER"},
+	"uint8":           {"SMALLINT", "TINYINT UNSIGNED", "INTEGER"},
+	"byte":            {"SMALLINT", "TINYINT UNSIGNED", "INTEGER"},
+	"float64":         {"DOUBLE PRECISION", "DOUBLE", "REAL"},
+	"float32":         {"REAL", "FLOAT", "REAL"},
+	"time.Time":       {"TIMESTAMPTZ", "DATETIME", "DATETIME"},
+	"time.Duration":   {"BIGINT", "BIGINT", "INTEGER"},
+	"[]byte":          {"BYTEA", "BLOB", "BLOB"},
+	"[]uint8":         {"BYTEA", "BLOB", "BLOB"},
+	"json.RawMessage": {"JSONB", "JSON", "TEXT"},
+}
+
+// ToSQL generates CREATE TABLE statements for the diagram in the given dialect.
+// Tables are named after the entities' keys in the diagram and ordered so
+// that referenced tables are created first; many-to-many relationships
+// produce join tables after all entity tables, one per pair of entities. It
+// returns an error for entities without attributes, which cannot be created
+// as tables, and for tables whose names collide.
+func (d *Diagram) ToSQL(dialect Dialect) (string, error) {
+	if !isValidDialect(dialect) {
+		return "", fmt.Errorf("unsupported SQL dialect: %q", dialect)
+	}
+
+	// A table needs at least one column
+	for _, name := range d.entityNames() {
+		if len(d.Entities[name].Attributes) == 0 {
+			return "", fmt.Errorf("entity %q has no attributes to create a table from", name)
+		}
+	}
+
+	var sb strings.Builder
+
+	// Add title if present
+	if d.Title != "" {
+		sb.WriteString(fmt.Sprintf("-- %s\n\n", strings.ReplaceAll(d.Title, "\n", " ")))
+	}
+
+	tables := d.sqlTables(dialect)
+	names := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		if names[table.Name] {
+			return "", fmt.Errorf("more than one table is named %q", table.Name)
+		}
+		names[table.Name] = true
+	}
+
+	for i, table := range tables {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(formatSQLTable(table, dialect))
+	}
+
+	return sb.String(), nil
+}
+
+// sqlTables converts the diagram into tables in dependency order.
+func (d *Diagram) sqlTables(dialect Dialect) []*sqlTable {
+	tables := make(map[string]*sqlTable, len(d.Entities))
+	for _, name := range d.entityNames() {
+		tables[name] = sqlTableFromEntity(name, d.Entities[name], dialect)
+	}
+
+	var joins []*sqlTable
+	joined := make(map[[2]string]bool)
+	linked := make(map[string]bool)
+
+	// Foreign keys declared with a reference
+	for _, name := range d.entityNames() {
+		for _, key := range d.Entities[name].Keys {
+			fk := d.sqlForeignKeyFromKey(key)
+			if fk == nil {
+				continue
+			}
+			for _, col := range fk.Columns {
+				linked[name+"."+col] = true
+			}
+			tables[name].ForeignKeys = append(tables[name].ForeignKeys, *fk)
+		}
+	}
+
+	// Foreign keys from relationships
+	for _, rel := range d.Relationships {
+		if rel.Cardinality == ManyToMany {
+			// A many-to-many relationship declared from both sides shares one join table
+			pair := [2]string{min(rel.From, rel.To), max(rel.From, rel.To)}
+			if joined[pair] {
+				continue
+			}
+			if join := d.sqlJoinTable(rel, dialect); join != nil {
+				joined[pair] = true
+				joins = append(joins, join)
+			}
+			continue
+		}
+
+		if holder, fk := d.sqlForeignKeyFromRelationship(rel); fk != nil {
+			if !slices.ContainsFunc(fk.Columns, func(col string) bool { return linked[holder+"."+col] }) {
+				for _, col := range fk.Columns {
+					linked[holder+"."+col] = true
+				}
+				tables[holder].ForeignKeys = append(tables[holder].ForeignKeys, *fk)
+			}
+			continue
+		}
+
+		holder, target, fk := d.relationshipForeignKey(rel)
+		if fk == nil || linked[holder+"."+fk.Name] {
+			continue
+		}
+		linked[holder+"."+fk.Name] = true
+		addSQLForeignKey(tables[holder], fk.Name, target, d.Entities[target])
+	}
+
+	// Foreign keys from fk-tagged attributes not covered by a relationship
+	for _, name := range d.entityNames() {
+		for _, attr := range d.Entities[name].Attributes {
+			if !hasKey(d.Entities[name], attr, ForeignKey) || linked[name+"."+attr.Name] {
+				continue
+			}
+			if target := d.entityForKeyName(attr.Name); target != "" {
+				addSQLForeignKey(tables[name], attr.Name, target, d.Entities[target])
+			}
+		}
+	}
+
+	return append(orderSQLTables(tables), joins...)
+}
+
+// sqlForeignKeyFromKey converts a foreign key declared with a reference
+// into a constraint. A reference without attributes points at the primary
+// key of the referenced entity. It returns nil for other keys and for
+// references to entities that do not exist.
+func (d *Diagram) sqlForeignKeyFromKey(key *Key) *sqlForeignKey {
+	if key.Type != ForeignKey || key.Reference == nil || len(key.Attributes) == 0 {
+		return nil
+	}
+	target, ok := d.resolveEntity(key.Reference.Entity)
+	if !ok {
+		return nil
+	}
+
+	refColumns := referencedColumns(d.Entities[target], key)
+	if len(refColumns) != len(key.Attributes) {
+		return nil
+	}
+
+	return &sqlForeignKey{
+		Columns:    key.Attributes,
+		RefTable:   sanitizeName(target),
+		RefColumns: refColumns,
+	}
+}
+
+// sqlForeignKeyFromRelationship converts the attributes a relationship
+// records into a constraint on the entity holding them: the many side, or
+// for one-to-one relationships the side whose attributes are not its primary
+// key. It returns nil for relationships without attributes.
+func (d *Diagram) sqlForeignKeyFromRelationship(rel *Relationship) (string, *sqlForeignKey) {
+	if len(rel.FromAttributes) == 0 || len(rel.FromAttributes) != len(rel.ToAttributes) {
+		return "", nil
+	}
+	from, ok := d.Entities[rel.From]
+	if !ok {
+		return "", nil
+	}
+	to, ok := d.Entities[rel.To]
+	if !ok {
+		return "", nil
+	}
+
+	if rel.Cardinality == OneToMany ||
+		(rel.Cardinality == OneToOne && isPrimaryKey(from, rel.FromAttributes) && !isPrimaryKey(to, rel.ToAttributes)) {
+		return rel.To, &sqlForeignKey{
+			Columns:    rel.ToAttributes,
+			RefTable:   sanitizeName(rel.From),
+			RefColumns: rel.FromAttributes,
+		}
+	}
+	return rel.From, &sqlForeignKey{
+		Columns:    rel.FromAttributes,
+		RefTable:   sanitizeName(rel.To),
+		RefColumns: rel.ToAttributes,
+	}
+}
+
+// relationshipForeignKey determines which entity holds the foreign key for a
+// relationship and the attribute carrying it. It returns a nil attribute when
+// no matching fk-tagged attribute exists.
+func (d *Diagram) relationshipForeignKey(rel *Relationship) (holder, target string, fk *Attribute) {
+	from, ok := d.Entities[rel.From]
+	if !ok {
+		return "", "", nil
+	}
+	to, ok := d.Entities[rel.To]
+	if !ok {
+		return "", "", nil
+	}
+
+	switch rel.Cardinality {
+	case OneToMany:
+		return rel.To, rel.From, foreignKeyFor(to, from)
+	case ManyToOne:
+		return rel.From, rel.To, foreignKeyFor(from, to)
+	case OneToOne:
+		// Either side may hold the reference; prefer the declaring side
+		if fk := foreignKeyFor(from, to); fk != nil {
+			return rel.From, rel.To, fk
+		}
+		return rel.To, rel.From, foreignKeyFor(to, from)
+	default:
+		return "", "", nil
+	}
+}
+
+// sqlJoinTable builds the join table for a many-to-many relationship.
+// It returns nil if either side cannot be referenced.
+func (d *Diagram) sqlJoinTable(rel *Relationship, dialect Dialect) *sqlTable {
+	from, ok := d.Entities[rel.From]
+	if !ok {
+		return nil
+	}
+	to, ok := d.Entities[rel.To]
+	if !ok {
+		return nil
+	}
+
+	fromRef := referencedColumn(from)
+	toRef := referencedColumn(to)
+	if fromRef == nil || toRef == nil {
+		return nil
+	}
+
+	fromCol := from.Name + fromRef.Name
+	toCol := to.Name + toRef.Name
+	if fromCol == toCol {
+		toCol = rel.Field + toRef.Name
+	}
+
+	return &sqlTable{
+		Name: sanitizeName(rel.From + "_" + rel.Field),
+		Columns: []sqlColumn{
+			{Name: fromCol, Type: sqlColumnType(fromRef.Type, dialect)},
+			{Name: toCol, Type: sqlColumnType(toRef.Type, dialect)},
+		},
+		PrimaryKey: []string{fromCol, toCol},
+		ForeignKeys: []sqlForeignKey{
+			{Columns: []string{fromCol}, RefTable: sanitizeName(rel.From), RefColumns: []string{fromRef.Name}},
+			{Columns: []string{toCol}, RefTable: sanitizeName(rel.To), RefColumns: []string{toRef.Name}},
+		},
+	}
+}
+
+// sqlTableFromEntity converts an entity into a table named after its key,
+// without foreign keys.
+func sqlTableFromEntity(name string, entity *Entity, dialect Dialect) *sqlTable {
+	table := &sqlTable{
+		Name: sanitizeName(name),
+		Note: entity.Note,
+	}
+
+	for _, attr := range entity.Attributes {
+		table.Columns = append(table.Columns, sqlColumn{
+			Name:     attr.Name,
+			Type:     sqlColumnType(attr.Type, dialect),
+			Nullable: attr.Nullable,
+			Note:     attr.Note,
+		})
+
+		if hasKey(entity, attr, PrimaryKey) {
+			table.PrimaryKey = append(table.PrimaryKey, attr.Name)
+		}
+		if attr.Key != nil && *attr.Key == UniqueKey {
+			table.Unique = append(table.Unique, []string{attr.Name})
+		}
+	}
+
+	// Unique keys declared as groups, which may span several columns
+	for _, key := range entity.Keys {
+		if key.Type != UniqueKey || len(key.Attributes) == 0 {
+			continue
+		}
+		if !slices.ContainsFunc(table.Unique, func(cols []string) bool { return slices.Equal(cols, key.Attributes) }) {
+			table.Unique = append(table.Unique, key.Attributes)
+		}
+	}
+
+	return table
+}
+
+// addSQLForeignKey adds a foreign key from column to the referenced
+// column of target, keyed by name in the diagram, skipping duplicates.
+func addSQLForeignKey(table *sqlTable, column, name string, target *Entity) {
+	ref := referencedColumn(target)
+	if table == nil || ref == nil {
+		return
+	}
+	for _, fk := range table.ForeignKeys {
+		if len(fk.Columns) == 1 && fk.Columns[0] == column {
+			return
+		}
+	}
+	table.ForeignKeys = append(table.ForeignKeys, sqlForeignKey{
+		Columns:    []string{column},
+		RefTable:   sanitizeName(name),
+		RefColumns: []string{ref.Name},
+	})
+}
+
+// orderSQLTables sorts tables so that every table follows the tables it
+// references. Ties are broken by name; tables in a reference cycle are
+// appended in name order.
+func orderSQLTables(tables map[string]*sqlTable) []*sqlTable {
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	byTable := make(map[string]string, len(tables))
+	for _, name := range names {
+		byTable[tables[name].Name] = name
+	}
+
+	done := make(map[string]bool, len(tables))
+	ordered := make([]*sqlTable, 0, len(tables))
+
+	for len(ordered) < len(names) {
+		progressed := false
+		for _, name := range names {
+			if done[name] || !sqlDependenciesDone(tables[name], byTable, done, name) {
+				continue
+			}
+			done[name] = true
+			ordered = append(ordered, tables[name])
+			progressed = true
+			break
+		}
+		if progressed {
+			continue
+		}
+		// Reference cycle: emit the remaining tables in name order
+		for _, name := range names {
+			if !done[name] {
+				done[name] = true
+				ordered = append(ordered, tables[name])
+			}
+		}
+	}
+
+	return ordered
+}
+
+// sqlDependenciesDone reports whether every table referenced by table has
+// already been emitted. Self references are ignored.
+func sqlDependenciesDone(table *sqlTable, byTable map[string]string, done map[string]bool, self string) bool {
+	for _, fk := range table.ForeignKeys {
+		ref, ok := byTable[fk.RefTable]
+		if ok && ref != self && !done[ref] {
+			return false
+		}
+	}
+	return true
+}
+
+// formatSQLTable formats a table as a CREATE TABLE statement, followed by
+// any comment statements the dialect needs.
+func formatSQLTable(table *sqlTable, dialect Dialect) string {
+	var sb strings.Builder
+
+	if table.Note != nil && dialect == SQLite {
+		sb.WriteString(fmt.Sprintf("-- %s\n", strings.ReplaceAll(*table.Note, "\n", " ")))
+	}
+
+	sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", quoteSQL(table.Name, dialect)))
+
+	lines := make([]string, 0, len(table.Columns)+len(table.Unique)+len(table.ForeignKeys)+1)
+	for _, col := range table.Columns {
+		lines = append(lines, formatSQLColumn(col, dialect))
+	}
+	if len(table.PrimaryKey) > 0 {
+		lines = append(lines, fmt.Sprintf("CONSTRAINT %s PRIMARY KEY (%s)",
+			quoteSQL(constraintName(table.Name, nil, "pkey"), dialect),
+			quoteSQLList(table.PrimaryKey, dialect)))
+	}
+	for _, unique := range table.Unique {
+		lines = append(lines, fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)",
+			quoteSQL(constraintName(table.Name, unique, "key"), dialect),
+			quoteSQLList(unique, dialect)))
+	}
+	for _, fk := range table.ForeignKeys {
+		lines = append(lines, fmt.Sprintf("CONSTRAINT %s %s",
+			quoteSQL(constraintName(table.Name, fk.Columns, "fkey"), dialect),
+			formatSQLForeignKey(fk, dialect)))
+	}
+
+	sb.WriteString("    ")
+	sb.WriteString(strings.Join(lines, ",\n    "))
+	sb.WriteString("\n)")
+
+	if table.Note != nil && dialect == MySQL {
+		sb.WriteString(fmt.Sprintf(" COMMENT=%s", quoteSQLString(*table.Note)))
+	}
+	sb.WriteString(";\n")
+
+	// PostgreSQL attaches comments with separate statements
+	if dialect == PostgreSQL {
+		if table.Note != nil {
+			sb.WriteString(fmt.Sprintf("COMMENT ON TABLE %s IS %s;\n",
+				quoteSQL(table.Name, dialect),
+				quoteSQLString(*table.Note)))
+		}
+		for _, col := range table.Columns {
+			if col.Note != nil {
+				sb.WriteString(fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;\n",
+					quoteSQL(table.Name, dialect),
+					quoteSQL(col.Name, dialect),
+					quoteSQLString(*col.Note)))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// formatSQLColumn formats a column definition.
+func formatSQLColumn(col sqlColumn, dialect Dialect) string {
+	line := fmt.Sprintf("%s %s", quoteSQL(col.Name, dialect), col.Type)
+	if !col.Nullable {
+		line += " NOT NULL"
+	}
+	if col.Note != nil && dialect == MySQL {
+		line += " COMMENT " + quoteSQLString(*col.Note)
+	}
+	return line
+}
+
+// formatSQLForeignKey formats a FOREIGN KEY table constraint.
+func formatSQLForeignKey(fk sqlForeignKey, dialect Dialect) string {
+	return fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quoteSQLList(fk.Columns, dialect),
+		quoteSQL(fk.RefTable, dialect),
+		quoteSQLList(fk.RefColumns, dialect))
+}
+
+// maxConstraintName is the longest identifier PostgreSQL keeps; MySQL
+// allows 64 characters.
+const maxConstraintName = 63
+
+// constraintName names a constraint after PostgreSQL's defaults, such as
+// Order_pkey, User_Email_key or Order_UserID_fkey. Names longer than every
+// dialect keeps are cut and end in a hash of the full name, so long names
+// sharing a prefix stay distinct. ToSQL creates constraints under these
+// names and MigrationSQL drops them by the same names.
+func constraintName(table string, columns []string, suffix string) string {
+	name := table + "_" + suffix
+	if len(columns) > 0 {
+		name = table + "_" + strings.Join(columns, "_") + "_" + suffix
+	}
+	if len(name) > maxConstraintName {
+		h := fnv.New32a()
+		h.Write([]byte(name))
+		hash := fmt.Sprintf("_%08x", h.Sum32())
+		name = name[:maxConstraintName-len(hash)] + hash
+	}
+	return name
+}
+
+// sqlColumnType maps a Go type name to a column type for the dialect.
+// Collections and maps are stored as JSON; unknown types fall back to text.
+func sqlColumnType(goType string, dialect Dialect) string {
+	goType = strings.TrimPrefix(goType, "*")
+
+	t, ok := sqlTypes[goType]
+	switch {
+	case ok:
+	case strings.HasSuffix(goType, ".UUID"):
+		t = sqlType{"UUID", "CHAR(36)", "TEXT"}
+	case strings.HasPrefix(goType, "[]"), strings.HasPrefix(goType, "map["):
+		t = sqlType{"JSONB", "JSON", "TEXT"}
+	default:
+		t = sqlType{"TEXT", "TEXT", "TEXT"}
+	}
+
+	switch dialect {
+	case MySQL:
+		return t.MySQL
+	case SQLite:
+		return t.SQLite
+	default:
+		return t.Postgres
+	}
+}
+
+// quoteSQL quotes an identifier for the dialect.
+func quoteSQL(name string, dialect Dialect) string {
+	if dialect == MySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return "\"" + strings.ReplaceAll(name, "\"", "\"\"") + "\""
+}
+
+// quoteSQLList quotes and joins a list of identifiers.
+func quoteSQLList(names []string, dialect Dialect) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteSQL(name, dialect)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// quoteSQLString quotes a string literal.
+func quoteSQLString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// isValidDialect checks if a dialect is supported.
+func isValidDialect(dialect Dialect) bool {
+	switch dialect {
+	case PostgreSQL, MySQL, SQLite:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,352 @@
+package erd
+
+import (
+	"strings"
+	"testing"
+)
+
+func testSQLDiagram() *Diagram {
+	return NewDiagram("Shop").
+		AddEntity(NewEntity("User").
+			WithNote("Registered customer").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("Email", "string").WithUnique()).
+			AddAttribute(NewAttribute("Bio", "string").WithNullable().WithNote("Free text"))).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("ID", "int64").WithPrimaryKey()).
+			AddAttribute(NewAttribute("UserID", "string").WithForeignKey()).
+			AddAttribute(NewAttribute("ShopID", "string").WithForeignKey())).
+		AddEntity(NewEntity("Shop").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Group").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany)).
+		AddRelationship(NewRelationship("User", "Group", "Groups", ManyToMany))
+}
+
+func TestToSQL_PostgreSQL(t *testing.T) {
+	output, err := testSQLDiagram().ToSQL(PostgreSQL)
+	if err != nil {
+		t.Fatalf("ToSQL() unexpected error: %v", err)
+	}
+
+	want := []string{
+		"-- Shop\n",
+		"CREATE TABLE \"User\" (\n    \"ID\" TEXT NOT NULL,\n    \"Email\" TEXT NOT NULL,\n    \"Bio\" TEXT,\n    CONSTRAINT \"User_pkey\" PRIMARY KEY (\"ID\"),\n    CONSTRAINT \"User_Email_key\" UNIQUE (\"Email\")\n);\n",
+		"COMMENT ON TABLE \"User\" IS 'Registered customer';\n",
+		"COMMENT ON COLUMN \"User\".\"Bio\" IS 'Free text';\n",
+		"\"ID\" BIGINT NOT NULL",
+		"FOREIGN KEY (\"UserID\") REFERENCES \"User\" (\"ID\")",
+		"FOREIGN KEY (\"ShopID\") REFERENCES \"Shop\" (\"ID\")",
+		"CREATE TABLE \"User_Groups\" (\n    \"UserID\" TEXT NOT NULL,\n    \"GroupID\" TEXT NOT NULL,\n    CONSTRAINT \"User_Groups_pkey\" PRIMARY KEY (\"UserID\", \"GroupID\"),",
+		"FOREIGN KEY (\"GroupID\") REFERENCES \"Group\" (\"ID\")",
+	}
+	for _, w := range want {
+		if !strings.Contains(output, w) {
+			t.Errorf("ToSQL() should contain %q, got:\n%s", w, output)
+		}
+	}
+
+	// Referenced tables are created before the tables referencing them
+	order := []string{`TABLE "Shop"`, `TABLE "User"`, `TABLE "Order"`, `TABLE "User_Groups"`}
+	for i := 1; i < len(order); i++ {
+		if strings.Index(output, order[i-1]) > strings.Index(output, order[i]) {
+			t.Errorf("ToSQL() should create %s before %s, got:\n%s", order[i-1], order[i], output)
+		}
+	}
+}
+
+func TestToSQL_MySQL(t *testing.T) {
+	output, err := testSQLDiagram().ToSQL(MySQL)
+	if err != nil {
+		t.Fatalf("ToSQL() unexpected error: %v", err)
+	}
+
+	want := []string{
+		"CREATE TABLE `User` (",
+		"`ID` VARCHAR(255) NOT NULL",
+		"`Bio` VARCHAR(255) COMMENT 'Free text'",
+		") COMMENT='Registered customer';\n",
+		"FOREIGN KEY (`UserID`) REFERENCES `User` (`ID`)",
+	}
+	for _, w := range want {
+		if !strings.Contains(output, w) {
+			t.Errorf("ToSQL() should contain %q, got:\n%s", w, output)
+		}
+	}
+}
+
+func TestToSQL_SQLite(t *testing.T) {
+	output, err := testSQLDiagram().ToSQL(SQLite)
+	if err != nil {
+		t.Fatalf("ToSQL() unexpected error: %v", err)
+	}
+
+	want := []string{
+		"-- Registered customer\nCREATE TABLE \"User\" (",
+		"\"ID\" INTEGER NOT NULL",
+	}
+	for _, w := range want {
+		if !strings.Contains(output, w) {
+			t.Errorf("ToSQL() should contain %q, got:\n%s", w, output)
+		}
+	}
+	if strings.Contains(output, "COMMENT") {
+		t.Errorf("ToSQL() should not emit COMMENT for SQLite, got:\n%s", output)
+	}
+}
+
+func TestToSQL_UnsupportedDialect(t *testing.T) {
+	if _, err := testSQLDiagram().ToSQL(Dialect("oracle")); err == nil {
+		t.Error("ToSQL() expected error for unsupported dialect")
+	}
+}
+
+func TestToSQL_OneToOne(t *testing.T) {
+	diagram := NewDiagram("Test").
+		AddEntity(NewEntity("User").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Profile").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("UserID", "string").WithForeignKey())).
+		AddRelationship(NewRelationship("User", "Profile", "Profile", OneToOne))
+
+	output, err := diagram.ToSQL(PostgreSQL)
+	if err != nil {
+		t.Fatalf("ToSQL() unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `FOREIGN KEY ("UserID") REFERENCES "User" ("ID")`) {
+		t.Errorf("ToSQL() should place one-to-one key on the referencing side, got:\n%s", output)
+	}
+}
+
+func TestToSQL_RecordedAttributes(t *testing.T) {
+	diagram := NewDiagram("Test").
+		AddEntity(NewEntity("User").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("BuyerID", "string")).
+			AddAttribute(NewAttribute("SellerID", "string"))).
+		AddEntity(NewEntity("Profile").
+			AddAttribute(NewAttribute("Handle", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("Owner", "string"))).
+		AddRelationship(NewRelationship("User", "Order", "Purchases", OneToMany).
+			WithAttributes([]string{"ID"}, []string{"BuyerID"})).
+		AddRelationship(NewRelationship("Order", "User", "Seller", ManyToOne).
+			WithAttributes([]string{"SellerID"}, []string{"ID"})).
+		AddRelationship(NewRelationship("User", "Profile", "Profile", OneToOne).
+			WithAttributes([]string{"ID"}, []string{"Owner"}))
+
+	output, err := diagram.ToSQL(PostgreSQL)
+	if err != nil {
+		t.Fatalf("ToSQL() unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`FOREIGN KEY ("BuyerID") REFERENCES "User" ("ID")`,
+		`FOREIGN KEY ("SellerID") REFERENCES "User" ("ID")`,
+		`FOREIGN KEY ("Owner") REFERENCES "User" ("ID")`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("ToSQL() should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestToSQL_UnrelatedForeignKey(t *testing.T) {
+	diagram := NewDiagram("Shop").
+		AddEntity(NewEntity("User").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Shop").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Order").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("ShopID", "string").WithForeignKey()).
+			AddAttribute(NewAttribute("UserGroupID", "string").WithForeignKey())).
+		AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany))
+
+	output, err := diagram.ToSQL(PostgreSQL)
+	if err != nil {
+		t.Fatalf("ToSQL() unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `FOREIGN KEY ("ShopID") REFERENCES "Shop" ("ID")`) {
+		t.Errorf("ToSQL() should reference Shop from ShopID, got:\n%s", output)
+	}
+	if strings.Contains(output, `REFERENCES "User"`) {
+		t.Errorf("ToSQL() should not reference User without a matching foreign key, got:\n%s", output)
+	}
+}
+
+func TestToSQL_BidirectionalManyToMany(t *testing.T) {
+	diagram := NewDiagram("Test").
+		AddEntity(NewEntity("User").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddEntity(NewEntity("Group").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())).
+		AddRelationship(NewRelationship("User", "Group", "Groups", ManyToMany)).
+		AddRelationship(NewRelationship("Group", "User", "Members", ManyToMany))
+
+	output, err := diagram.ToSQL(PostgreSQL)
+	if err != nil {
+		t.Fatalf("ToSQL() unexpected error: %v", err)
+	}
+	if n := strings.Count(output, "CREATE TABLE"); n != 3 {
+		t.Errorf("ToSQL() should create one join table, got %d tables:\n%s", n, output)
+	}
+	if !strings.Contains(output, `CREATE TABLE "User_Groups"`) {
+		t.Errorf("ToSQL() should name the join table after the first relationship, got:\n%s", output)
+	}
+}
+
+func TestToSQL_EntityWithoutAttributes(t *testing.T) {
+	diagram := NewDiagram("Test").AddEntity(NewEntity("Empty"))
+
+	if _, err := diagram.ToSQL(PostgreSQL); err == nil || !strings.Contains(err.Error(), `"Empty"`) {
+		t.Errorf("ToSQL() error = %v, want an error naming the entity", err)
+	}
+}
+
+func TestToSQL_SameNameInPackages(t *testing.T) {
+	account := func(pkg string) *Entity {
+		return NewEntity("Account").WithPackage(pkg).
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey())
+	}
+	diagram := NewDiagram("Test").
+		AddEntity(account("billing")).
+		AddEntity(account("auth")).
+		AddEntity(NewEntity("Invoice").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("AccountID", "string")).
+			AddKey(NewKey("fk_account", ForeignKey, "AccountID").WithReference("billing.Account"))).
+		AddRelationship(NewRelationship("billing.Account", "auth.Account", "Logins", ManyToMany))
+
+	got, err := diagram.ToSQL(PostgreSQL)
+	if err != nil {
+		t.Fatalf("ToSQL() unexpected error: %v", err)
+	}
+
+	// Tables are named after the entity keys, so neither replaces the other
+	for _, want := range []string{
+		`CREATE TABLE "auth_Account" (`,
+		`CREATE TABLE "billing_Account" (`,
+		`CONSTRAINT "billing_Account_pkey" PRIMARY KEY ("ID")`,
+		`REFERENCES "billing_Account" ("ID")`,
+		`CREATE TABLE "billing_Account_Logins" (`,
+		`REFERENCES "auth_Account" ("ID")`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ToSQL() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `CREATE TABLE "Account"`) {
+		t.Errorf("ToSQL() should not create a bare Account table:\n%s", got)
+	}
+
+	// Keys that sanitize to the same table name are rejected
+	diagram = NewDiagram("Test").
+		AddEntity(NewEntity("Line Item").AddAttribute(NewAttribute("ID", "string"))).
+		AddEntity(NewEntity("Line_Item").AddAttribute(NewAttribute("ID", "string")))
+	if _, err := diagram.ToSQL(PostgreSQL); err == nil || !strings.Contains(err.Error(), `"Line_Item"`) {
+		t.Errorf("ToSQL() error = %v, want a table name collision", err)
+	}
+}
+
+func TestToSQL_Cycle(t *testing.T) {
+	diagram := NewDiagram("Test").
+		AddEntity(NewEntity("A").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("BID", "string").WithForeignKey())).
+		AddEntity(NewEntity("B").
+			AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()).
+			AddAttribute(NewAttribute("AID", "string").WithForeignKey()))
+
+	output, err := diagram.ToSQL(PostgreSQL)
+	if err != nil {
+		t.Fatalf("ToSQL() unexpected error: %v", err)
+	}
+	if strings.Count(output, "CREATE TABLE") != 2 {
+		t.Errorf("ToSQL() should emit every table in a cycle, got:\n%s", output)
+	}
+}
+
+func TestSQLColumnType(t *testing.T) {
+	tests := []struct {
+		goType  string
+		dialect Dialect
+		want    string
+	}{
+		{"string", PostgreSQL, "TEXT"},
+		{"string", MySQL, "VARCHAR(255)"},
+		{"*int64", PostgreSQL, "BIGINT"},
+		{"int64", SQLite, "INTEGER"},
+		{"float64", PostgreSQL, "DOUBLE PRECISION"},
+		{"time.Time", PostgreSQL, "TIMESTAMPTZ"},
+		{"time.Time", MySQL, "DATETIME"},
+		{"[]uint8", PostgreSQL, "BYTEA"},
+		{"uuid.UUID", PostgreSQL, "UUID"},
+		{"[]string", PostgreSQL, "JSONB"},
+		{"map[string]string", MySQL, "JSON"},
+		{"Custom", SQLite, "TEXT"},
+	}
+
+	for _, tt := range tests {
+		if got := sqlColumnType(tt.goType, tt.dialect); got != tt.want {
+			t.Errorf("sqlColumnType(%q, %s) = %q, want %q", tt.goType, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		dialect Dialect
+		want    string
+	}{
+		{"postgres", "User", PostgreSQL, `"User"`},
+		{"postgres embedded quote", `a"b`, PostgreSQL, `"a""b"`},
+		{"mysql", "User", MySQL, "`User`"},
+		{"mysql embedded backtick", "a`b", MySQL, "`a``b`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quoteSQL(tt.input, tt.dialect); got != tt.want {
+				t.Errorf("quoteSQL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidDialect(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		want    bool
+	}{
+		{PostgreSQL, true},
+		{MySQL, true},
+		{SQLite, true},
+		{Dialect("oracle"), false},
+	}
+
+	for _, tt := range tests {
+		if got := isValidDialect(tt.dialect); got != tt.want {
+			t.Errorf("isValidDialect(%q) = %v, want %v", tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestToSQL_CompositeKeys(t *testing.T) {
+	ddl, err := testCompositeDiagram().ToSQL(PostgreSQL)
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+
+	assertInOrder(t, ddl, []string{
+		"CREATE TABLE \"Tenant\" (",
+		"CREATE TABLE \"Order\" (",
+		"    CONSTRAINT \"Order_pkey\" PRIMARY KEY (\"TenantID\", \"ID\"),\n    CONSTRAINT \"Order_TenantID_Number_key\" UNIQUE (\"TenantID\", \"Number\"),\n    CONSTRAINT \"Order_TenantID_fkey\" FOREIGN KEY (\"TenantID\") REFERENCES \"Tenant\" (\"ID\")\n",
+		"CREATE TABLE \"LineItem\" (",
+		"    CONSTRAINT \"LineItem_pkey\" PRIMARY KEY (\"TenantID\", \"OrderID\", \"SKU\"),\n    CONSTRAINT \"LineItem_TenantID_OrderID_fkey\" FOREIGN KEY (\"TenantID\", \"OrderID\") REFERENCES \"Order\" (\"TenantID\", \"ID\")\n);\n",
+	})
+}
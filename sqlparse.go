@@ -0,0 +1,1012 @@
+package erd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// FromSQL parses SQL DDL into an ERD diagram.
+//
+// CREATE TABLE statements become entities, with column types mapped back to
+// Go types. PRIMARY KEY, UNIQUE and REFERENCES clauses, both inline and as
+// table constraints or ALTER TABLE ... ADD CONSTRAINT statements, set
+// attribute keys; each foreign key becomes a many-to-one relationship, or
+// one-to-one when the referencing columns are themselves unique, recording
+// the columns it joins. Tables that
+// consist solely of two foreign keys forming their primary key are treated as
+// join tables and become many-to-many relationships. Schema-qualified table
+// names populate [Entity.Package], and column comments become attribute notes.
+// Statements other than CREATE TABLE, ALTER TABLE and COMMENT ON are ignored.
+func FromSQL(title string, r io.Reader, dialect Dialect) (*Diagram, error) {
+	if !isValidDialect(dialect) {
+		return nil, fmt.Errorf("unsupported SQL dialect: %q", dialect)
+	}
+
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading SQL: %w", err)
+	}
+
+	tokens, err := tokenizeSQL(string(src), dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := newSQLSchema()
+	for _, stmt := range splitSQLStatements(tokens) {
+		p := &sqlParser{tokens: stmt}
+		if err := p.parseStatement(schema); err != nil {
+			return nil, err
+		}
+	}
+
+	return diagramFromSQLTables(title, schema.tables, dialect), nil
+}
+
+// diagramFromSQLTables converts parsed or introspected tables to a diagram.
+func diagramFromSQLTables(title string, tables []*sqlTable, dialect Dialect) *Diagram {
+	diagram := NewDiagram(title)
+
+	// Add entities first so tables sharing a name across schemas are keyed
+	// by qualified name before relationships refer to them
+	entities := make(map[*Entity]*sqlTable, len(tables))
+	for _, table := range tables {
+		if isSQLJoinTable(table) {
+			continue
+		}
+		entity := entityFromSQLTable(table, dialect)
+		diagram.AddEntity(entity)
+		entities[entity] = table
+	}
+
+	// Index entity keys by bare and schema-qualified table name for
+	// reference lookups
+	keys := make(map[*sqlTable]string, len(entities))
+	names := make(map[string]string, len(tables)*2)
+	for _, key := range diagram.entityNames() {
+		table := entities[diagram.Entities[key]]
+		keys[table] = key
+		if _, taken := names[strings.ToLower(table.Name)]; !taken || key == table.Name {
+			names[strings.ToLower(table.Name)] = key
+		}
+		if table.Schema != "" {
+			names[strings.ToLower(table.Schema+"."+table.Name)] = key
+		}
+	}
+	// Unqualified references prefer the referencing table's schema
+	resolve := func(table *sqlTable, ref string) string {
+		if table.Schema != "" {
+			if name, ok := names[strings.ToLower(table.Schema+"."+ref)]; ok {
+				return name
+			}
+		}
+		if name, ok := names[strings.ToLower(ref)]; ok {
+			return name
+		}
+		return ref
+	}
+
+	for _, table := range tables {
+		if isSQLJoinTable(table) {
+			// Follow column order, which catalogs may not preserve for constraints
+			first, second := table.ForeignKeys[0], table.ForeignKeys[1]
+			if strings.EqualFold(first.Columns[0], table.Columns[1].Name) {
+				first, second = second, first
+			}
+			from := resolve(table, first.RefTable)
+			to := resolve(table, second.RefTable)
+			field := table.Name
+			if entity := diagram.Entities[from]; entity != nil {
+				field = strings.TrimPrefix(table.Name, entity.Name+"_")
+			}
+			diagram.AddRelationship(NewRelationship(from, to, field, ManyToMany))
+			continue
+		}
+
+		for _, fk := range table.ForeignKeys {
+			cardinality := ManyToOne
+			if table.isUnique(fk.Columns) {
+				cardinality = OneToOne
+			}
+			target := resolve(table, fk.RefTable)
+			rel := NewRelationship(keys[table], target, strings.Join(fk.Columns, ","), cardinality)
+			if entity := diagram.Entities[target]; entity != nil {
+				refs := fk.RefColumns
+				if len(refs) == 0 {
+					refs = entities[entity].PrimaryKey
+				}
+				if attrs, ok := sqlAttributeNames(entity, refs); ok && len(attrs) == len(fk.Columns) {
+					rel.WithAttributes(fk.Columns, attrs)
+				}
+			}
+			diagram.AddRelationship(rel)
+
+			// Attributes show a single key, so composite foreign keys and
+			// foreign keys within the primary key are declared as key groups
+			if len(fk.Columns) > 1 || (len(fk.Columns) == 1 && containsFold(table.PrimaryKey, fk.Columns[0])) {
+				key := NewKey("fk_"+strings.Join(fk.Columns, "_"), ForeignKey, fk.Columns...).WithReference(target, fk.RefColumns...)
+				diagram.Entities[keys[table]].AddKey(key)
+			}
+		}
+	}
+
+	return diagram
+}
+
+// sqlAttributeNames returns the names of the entity's attributes for the
+// given columns, which SQL matches regardless of case. It reports false if a
+// column has no attribute.
+func sqlAttributeNames(entity *Entity, cols []string) ([]string, bool) {
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		j := slices.IndexFunc(entity.Attributes, func(attr *Attribute) bool {
+			return strings.EqualFold(attr.Name, col)
+		})
+		if j < 0 {
+			return nil, false
+		}
+		names[i] = entity.Attributes[j].Name
+	}
+	return names, true
+}
+
+// entityFromSQLTable converts a table to an entity.
+func entityFromSQLTable(table *sqlTable, dialect Dialect) *Entity {
+	entity := NewEntity(table.Name)
+
+	if table.Schema != "" {
+		entity.WithPackage(table.Schema)
+	}
+	if table.Note != nil {
+		entity.WithNote(*table.Note)
+	}
+
+	pk := make(map[string]bool, len(table.PrimaryKey))
+	for _, col := range table.PrimaryKey {
+		pk[col] = true
+	}
+	fk := make(map[string]bool)
+	for _, key := range table.ForeignKeys {
+		for _, col := range key.Columns {
+			fk[col] = true
+		}
+	}
+
+	for _, col := range table.Columns {
+		attr := NewAttribute(col.Name, goTypeForSQL(col.Type, dialect))
+
+		// Primary key columns are implicitly NOT NULL
+		if col.Nullable && !pk[col.Name] {
+			attr.WithNullable()
+		}
+		if col.Note != nil {
+			attr.WithNote(*col.Note)
+		}
+
+		switch {
+		case pk[col.Name]:
+			attr.WithPrimaryKey()
+		case fk[col.Name]:
+			attr.WithForeignKey()
+		case table.isUnique([]string{col.Name}):
+			attr.WithUnique()
+		}
+
+		entity.AddAttribute(attr)
+	}
+
+	for _, cols := range table.Unique {
+		if len(cols) > 1 {
+			entity.AddKey(NewKey("uk_"+strings.Join(cols, "_"), UniqueKey, cols...))
+		}
+	}
+
+	return entity
+}
+
+// isSQLJoinTable reports whether a table only links two other tables: it has
+// exactly two single-column foreign keys that together form its primary key
+// and no other columns.
+func isSQLJoinTable(table *sqlTable) bool {
+	if len(table.Columns) != 2 || len(table.ForeignKeys) != 2 || len(table.PrimaryKey) != 2 {
+		return false
+	}
+	for _, fk := range table.ForeignKeys {
+		if len(fk.Columns) != 1 || !containsFold(table.PrimaryKey, fk.Columns[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isUnique reports whether the given columns are exactly the table's primary
+// key or one of its unique constraints.
+func (t *sqlTable) isUnique(columns []string) bool {
+	if sameColumns(t.PrimaryKey, columns) {
+		return true
+	}
+	for _, unique := range t.Unique {
+		if sameColumns(unique, columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameColumns compares two column lists ignoring order and case.
+func sameColumns(a, b []string) bool {
+	if len(a) == 0 || len(a) != len(b) {
+		return false
+	}
+	for _, col := range a {
+		if !containsFold(b, col) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// goTypeForSQL maps a SQL column type back to a Go type name.
+// Unknown types are returned unchanged.
+func goTypeForSQL(sqlType string, dialect Dialect) string {
+	t := strings.ToLower(strings.TrimSpace(sqlType))
+
+	// PostgreSQL arrays
+	if strings.HasSuffix(t, "[]") {
+		return "[]" + goTypeForSQL(strings.TrimSuffix(t, "[]"), dialect)
+	}
+
+	// MySQL's conventional boolean
+	if dialect == MySQL && strings.HasPrefix(t, "tinyint(1)") {
+		return "bool"
+	}
+
+	unsigned := strings.Contains(t, "unsigned")
+	t = strings.TrimSpace(strings.ReplaceAll(t, "unsigned", ""))
+	if i := strings.Index(t, "("); i >= 0 {
+		t = strings.TrimSpace(t[:i])
+	}
+
+	switch t {
+	case "text", "varchar", "char", "character", "character varying", "nvarchar", "nchar",
+		"citext", "clob", "tinytext", "mediumtext", "longtext", "enum", "set", "uuid":
+		return "string"
+	case "boolean", "bool":
+		return "bool"
+	case "bigint", "int8", "bigserial", "serial8":
+		if unsigned {
+			return "uint64"
+		}
+		return "int64"
+	case "integer", "int", "int4", "mediumint", "serial", "serial4":
+		switch {
+		case dialect == SQLite:
+			return "int64"
+		case unsigned:
+			return "uint32"
+		default:
+			return "int32"
+		}
+	case "smallint", "int2", "smallserial", "serial2":
+		if unsigned {
+			return "uint16"
+		}
+		return "int16"
+	case "tinyint":
+		if unsigned {
+			return "uint8"
+		}
+		return "int8"
+	case "real", "float4", "float":
+		if dialect == SQLite {
+			return "float64"
+		}
+		return "float32"
+	case "double", "double precision", "float8", "numeric", "decimal":
+		return "float64"
+	case "date", "time", "datetime", "timestamp", "timestamptz",
+		"timestamp with time zone", "timestamp without time zone", "time with time zone", "time without time zone":
+		return "time.Time"
+	case "interval":
+		return "time.Duration"
+	case "bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary":
+		return "[]byte"
+	case "json", "jsonb":
+		return "json.RawMessage"
+	default:
+		return sqlType
+	}
+}
+
+// sqlSchema accumulates tables while parsing DDL.
+type sqlSchema struct {
+	byName map[string]*sqlTable
+	tables []*sqlTable
+}
+
+// newSQLSchema creates an empty schema.
+func newSQLSchema() *sqlSchema {
+	return &sqlSchema{byName: make(map[string]*sqlTable)}
+}
+
+// add registers a table, replacing any earlier definition with the same name.
+func (s *sqlSchema) add(table *sqlTable) {
+	key := strings.ToLower(table.Schema + "." + table.Name)
+	if existing, ok := s.byName[key]; ok {
+		*existing = *table
+		return
+	}
+	s.byName[key] = table
+	s.tables = append(s.tables, table)
+}
+
+// lookup finds a table by optionally schema-qualified name.
+func (s *sqlSchema) lookup(schema, name string) *sqlTable {
+	if table, ok := s.byName[strings.ToLower(schema+"."+name)]; ok {
+		return table
+	}
+	for _, table := range s.tables {
+		if strings.EqualFold(table.Name, name) {
+			return table
+		}
+	}
+	return nil
+}
+
+// sqlTokenKind classifies SQL tokens.
+type sqlTokenKind int
+
+// SQL token kinds.
+const (
+	sqlWord sqlTokenKind = iota
+	sqlQuoted
+	sqlString
+	sqlNumber
+	sqlSymbol
+)
+
+// sqlToken is a lexical token of SQL source.
+type sqlToken struct {
+	text string
+	kind sqlTokenKind
+	line int
+}
+
+// tokenizeSQL splits SQL source into tokens, dropping whitespace and comments.
+func tokenizeSQL(src string, dialect Dialect) ([]sqlToken, error) {
+	var tokens []sqlToken
+	line := 1
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '-' && i+1 < len(src) && src[i+1] == '-',
+			c == '#' && dialect == MySQL:
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`' || (c == '[' && dialect == SQLite):
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			text, n, ok := scanSQLQuoted(src[i:], closing)
+			if !ok {
+				return nil, fmt.Errorf("line %d: unterminated %c", line, c)
+			}
+			kind := sqlQuoted
+			if c == '\'' {
+				kind = sqlString
+			}
+			tokens = append(tokens, sqlToken{text: text, kind: kind, line: line})
+			line += strings.Count(src[i:i+n], "\n")
+			i += n
+		case isSQLWordByte(c):
+			start := i
+			for i < len(src) && isSQLWordByte(src[i]) {
+				i++
+			}
+			kind := sqlWord
+			if c >= '0' && c <= '9' {
+				kind = sqlNumber
+			}
+			tokens = append(tokens, sqlToken{text: src[start:i], kind: kind, line: line})
+		default:
+			tokens = append(tokens, sqlToken{text: string(c), kind: sqlSymbol, line: line})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+// scanSQLQuoted scans a quoted token starting at s[0], where a doubled
+// closing character escapes itself. It returns the unquoted text and the
+// number of bytes consumed.
+func scanSQLQuoted(s string, closing byte) (text string, n int, ok bool) {
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != closing {
+			sb.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == closing && closing != ']' {
+			sb.WriteByte(closing)
+			i++
+			continue
+		}
+		return sb.String(), i + 1, true
+	}
+	return "", 0, false
+}
+
+// isSQLWordByte reports whether c can be part of a bare word or number.
+func isSQLWordByte(c byte) bool {
+	return c == '_' || c == '$' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
+		c >= 0x80
+}
+
+// splitSQLStatements splits tokens into statements at semicolons.
+func splitSQLStatements(tokens []sqlToken) [][]sqlToken {
+	var stmts [][]sqlToken
+	start := 0
+	for i, tok := range tokens {
+		if tok.kind == sqlSymbol && tok.text == ";" {
+			if i > start {
+				stmts = append(stmts, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		stmts = append(stmts, tokens[start:])
+	}
+	return stmts
+}
+
+// sqlParser parses a single statement.
+type sqlParser struct {
+	tokens []sqlToken
+	pos    int
+}
+
+// parseStatement parses a statement and records its effect on schema.
+func (p *sqlParser) parseStatement(schema *sqlSchema) error {
+	switch {
+	case p.acceptKeywords("CREATE"):
+		p.acceptKeywords("OR", "REPLACE")
+		if !p.acceptKeywords("TEMPORARY") {
+			p.acceptKeywords("TEMP")
+		}
+		if !p.acceptKeywords("TABLE") {
+			return nil
+		}
+		return p.parseCreateTable(schema)
+	case p.acceptKeywords("ALTER", "TABLE"):
+		return p.parseAlterTable(schema)
+	case p.acceptKeywords("COMMENT", "ON"):
+		return p.parseComment(schema)
+	default:
+		return nil
+	}
+}
+
+// parseCreateTable parses the remainder of a CREATE TABLE statement.
+func (p *sqlParser) parseCreateTable(schema *sqlSchema) error {
+	p.acceptKeywords("IF", "NOT", "EXISTS")
+
+	schemaName, name, err := p.parseQualifiedName()
+	if err != nil {
+		return err
+	}
+	table := &sqlTable{Schema: schemaName, Name: name}
+
+	// CREATE TABLE ... AS SELECT and similar forms carry no definitions
+	if !p.acceptSymbol("(") {
+		return nil
+	}
+
+	for {
+		if err := p.parseTableElement(table); err != nil {
+			return err
+		}
+		if p.acceptSymbol(",") {
+			continue
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return err
+		}
+		break
+	}
+
+	// Table options; only MySQL's COMMENT is of interest
+	for !p.atEnd() {
+		if p.acceptKeywords("COMMENT") {
+			p.acceptSymbol("=")
+			if tok := p.next(); tok.kind == sqlString {
+				note := tok.text
+				table.Note = &note
+			}
+			continue
+		}
+		p.next()
+	}
+
+	schema.add(table)
+	return nil
+}
+
+// parseTableElement parses a column definition or table constraint.
+func (p *sqlParser) parseTableElement(table *sqlTable) error {
+	if p.acceptKeywords("CONSTRAINT") {
+		p.next()
+		return p.parseTableConstraint(table)
+	}
+
+	tok := p.peek()
+	if tok.kind == sqlWord {
+		switch strings.ToUpper(tok.text) {
+		case "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "KEY", "INDEX", "FULLTEXT", "SPATIAL", "EXCLUDE":
+			return p.parseTableConstraint(table)
+		}
+	}
+
+	return p.parseColumn(table)
+}
+
+// parseTableConstraint parses a table-level constraint, recording primary,
+// unique and foreign keys and skipping anything else.
+func (p *sqlParser) parseTableConstraint(table *sqlTable) error {
+	switch {
+	case p.acceptKeywords("PRIMARY", "KEY"):
+		cols, err := p.parseColumnList()
+		if err != nil {
+			return err
+		}
+		table.PrimaryKey = cols
+	case p.acceptKeywords("UNIQUE"):
+		if !p.acceptKeywords("KEY") {
+			p.acceptKeywords("INDEX")
+		}
+		if !p.peekSymbol("(") {
+			p.next() // index name
+		}
+		cols, err := p.parseColumnList()
+		if err != nil {
+			return err
+		}
+		table.Unique = append(table.Unique, cols)
+	case p.acceptKeywords("FOREIGN", "KEY"):
+		if !p.peekSymbol("(") {
+			p.next() // index name
+		}
+		cols, err := p.parseColumnList()
+		if err != nil {
+			return err
+		}
+		if !p.acceptKeywords("REFERENCES") {
+			return p.errorf("expected REFERENCES")
+		}
+		fk, err := p.parseReferences(cols)
+		if err != nil {
+			return err
+		}
+		table.ForeignKeys = append(table.ForeignKeys, fk)
+	}
+
+	p.skipElement()
+	return nil
+}
+
+// parseColumn parses a column definition with its inline constraints.
+func (p *sqlParser) parseColumn(table *sqlTable) error {
+	nameTok := p.next()
+	if nameTok.kind != sqlWord && nameTok.kind != sqlQuoted {
+		return p.errorf("expected column name")
+	}
+	col := sqlColumn{Name: nameTok.text, Nullable: true}
+
+	// The type runs until the first column constraint keyword
+	var typeParts []string
+	for !p.atEnd() && !p.peekSymbol(",") && !p.peekSymbol(")") && !p.peekColumnConstraint() {
+		if p.peekSymbol("(") {
+			typeParts = append(typeParts, p.collectGroup())
+			continue
+		}
+		typeParts = append(typeParts, p.next().text)
+	}
+	col.Type = joinSQLType(typeParts)
+
+	for !p.atEnd() && !p.peekSymbol(",") && !p.peekSymbol(")") {
+		switch {
+		case p.acceptKeywords("NOT", "NULL"):
+			col.Nullable = false
+		case p.acceptKeywords("NULL"):
+			col.Nullable = true
+		case p.acceptKeywords("PRIMARY", "KEY"):
+			col.Nullable = false
+			table.PrimaryKey = []string{col.Name}
+		case p.acceptKeywords("UNIQUE"):
+			p.acceptKeywords("KEY")
+			table.Unique = append(table.Unique, []string{col.Name})
+		case p.acceptKeywords("REFERENCES"):
+			fk, err := p.parseReferences([]string{col.Name})
+			if err != nil {
+				return err
+			}
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		case p.acceptKeywords("COMMENT"):
+			if tok := p.next(); tok.kind == sqlString {
+				note := tok.text
+				col.Note = &note
+			}
+		case p.acceptKeywords("DEFAULT"):
+			p.skipDefault()
+		case p.peekSymbol("("):
+			p.collectGroup()
+		default:
+			p.next()
+		}
+	}
+
+	table.Columns = append(table.Columns, col)
+	return nil
+}
+
+// skipDefault skips a DEFAULT expression: a literal, a function call or a
+// parenthesized expression, optionally followed by PostgreSQL casts.
+func (p *sqlParser) skipDefault() {
+	if p.acceptSymbol("-") || p.acceptSymbol("+") {
+		p.next()
+	} else if p.peekSymbol("(") {
+		p.collectGroup()
+	} else {
+		p.next()
+		if p.peekSymbol("(") {
+			p.collectGroup()
+		}
+	}
+	for p.acceptSymbol(":") && p.acceptSymbol(":") {
+		p.next()
+	}
+}
+
+// parseReferences parses the target of a REFERENCES clause.
+func (p *sqlParser) parseReferences(cols []string) (sqlForeignKey, error) {
+	schemaName, name, err := p.parseQualifiedName()
+	if err != nil {
+		return sqlForeignKey{}, err
+	}
+	ref := name
+	if schemaName != "" {
+		ref = schemaName + "." + name
+	}
+
+	fk := sqlForeignKey{Columns: cols, RefTable: ref}
+	if p.peekSymbol("(") {
+		refCols, err := p.parseColumnList()
+		if err != nil {
+			return sqlForeignKey{}, err
+		}
+		fk.RefColumns = refCols
+	}
+	return fk, nil
+}
+
+// parseAlterTable parses ALTER TABLE ... ADD [CONSTRAINT name] constraint.
+// Other alterations are ignored.
+func (p *sqlParser) parseAlterTable(schema *sqlSchema) error {
+	p.acceptKeywords("IF", "EXISTS")
+	p.acceptKeywords("ONLY")
+
+	schemaName, name, err := p.parseQualifiedName()
+	if err != nil {
+		return err
+	}
+	table := schema.lookup(schemaName, name)
+	if table == nil {
+		return nil
+	}
+
+	for !p.atEnd() {
+		if p.acceptKeywords("ADD") {
+			if p.acceptKeywords("CONSTRAINT") {
+				p.next()
+				if err := p.parseTableConstraint(table); err != nil {
+					return err
+				}
+			} else if p.peekKeyword("PRIMARY") || p.peekKeyword("UNIQUE") || p.peekKeyword("FOREIGN") {
+				if err := p.parseTableConstraint(table); err != nil {
+					return err
+				}
+			}
+		}
+		p.skipElement()
+		if !p.acceptSymbol(",") {
+			p.next()
+		}
+	}
+
+	return nil
+}
+
+// parseComment parses COMMENT ON TABLE|COLUMN name IS 'text'.
+func (p *sqlParser) parseComment(schema *sqlSchema) error {
+	switch {
+	case p.acceptKeywords("TABLE"):
+		schemaName, name, err := p.parseQualifiedName()
+		if err != nil {
+			return err
+		}
+		note, ok := p.parseCommentText()
+		if table := schema.lookup(schemaName, name); table != nil && ok {
+			table.Note = note
+		}
+	case p.acceptKeywords("COLUMN"):
+		parts, err := p.parseNameParts()
+		if err != nil {
+			return err
+		}
+		if len(parts) < 2 {
+			return p.errorf("expected table.column")
+		}
+		note, ok := p.parseCommentText()
+		schemaName := ""
+		if len(parts) > 2 {
+			schemaName = parts[len(parts)-3]
+		}
+		table := schema.lookup(schemaName, parts[len(parts)-2])
+		if table == nil || !ok {
+			return nil
+		}
+		for i := range table.Columns {
+			if strings.EqualFold(table.Columns[i].Name, parts[len(parts)-1]) {
+				table.Columns[i].Note = note
+			}
+		}
+	}
+	return nil
+}
+
+// parseCommentText parses IS 'text' or IS NULL.
+func (p *sqlParser) parseCommentText() (*string, bool) {
+	if !p.acceptKeywords("IS") {
+		return nil, false
+	}
+	tok := p.next()
+	if tok.kind != sqlString {
+		return nil, true
+	}
+	text := tok.text
+	return &text, true
+}
+
+// parseQualifiedName parses [schema.]name.
+func (p *sqlParser) parseQualifiedName() (schema, name string, err error) {
+	parts, err := p.parseNameParts()
+	if err != nil {
+		return "", "", err
+	}
+	name = parts[len(parts)-1]
+	if len(parts) > 1 {
+		schema = parts[len(parts)-2]
+	}
+	return schema, name, nil
+}
+
+// parseNameParts parses a dotted name.
+func (p *sqlParser) parseNameParts() ([]string, error) {
+	var parts []string
+	for {
+		tok := p.next()
+		if tok.kind != sqlWord && tok.kind != sqlQuoted {
+			return nil, p.errorf("expected name")
+		}
+		parts = append(parts, tok.text)
+		if !p.acceptSymbol(".") {
+			return parts, nil
+		}
+	}
+}
+
+// parseColumnList parses a parenthesized list of column names, ignoring
+// prefix lengths and sort orders.
+func (p *sqlParser) parseColumnList() ([]string, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	var cols []string
+	for {
+		tok := p.next()
+		if tok.kind != sqlWord && tok.kind != sqlQuoted {
+			return nil, p.errorf("expected column name")
+		}
+		cols = append(cols, tok.text)
+
+		// Skip anything up to the next separator, e.g. (255) or DESC
+		for !p.atEnd() && !p.peekSymbol(",") && !p.peekSymbol(")") {
+			if p.peekSymbol("(") {
+				p.collectGroup()
+				continue
+			}
+			p.next()
+		}
+		if p.acceptSymbol(",") {
+			continue
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return cols, nil
+	}
+}
+
+// peekColumnConstraint reports whether the next token starts a column constraint.
+func (p *sqlParser) peekColumnConstraint() bool {
+	tok := p.peek()
+	if tok.kind != sqlWord {
+		return false
+	}
+	switch strings.ToUpper(tok.text) {
+	case "NOT", "NULL", "PRIMARY", "UNIQUE", "REFERENCES", "DEFAULT", "CHECK", "CONSTRAINT",
+		"COLLATE", "AUTO_INCREMENT", "AUTOINCREMENT", "COMMENT", "GENERATED", "IDENTITY", "ON", "AS":
+		return true
+	default:
+		return false
+	}
+}
+
+// collectGroup consumes a parenthesized group and returns its source text.
+func (p *sqlParser) collectGroup() string {
+	var sb strings.Builder
+	depth := 0
+	for !p.atEnd() {
+		tok := p.next()
+		switch tok.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		switch {
+		case tok.kind == sqlString:
+			sb.WriteString(quoteSQLString(tok.text))
+		case tok.text == ",":
+			sb.WriteString(", ")
+		default:
+			sb.WriteString(tok.text)
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// skipElement skips tokens up to the next top-level comma or closing parenthesis.
+func (p *sqlParser) skipElement() {
+	for !p.atEnd() && !p.peekSymbol(",") && !p.peekSymbol(")") {
+		if p.peekSymbol("(") {
+			p.collectGroup()
+			continue
+		}
+		p.next()
+	}
+}
+
+// joinSQLType reassembles type tokens, keeping arguments and array
+// brackets attached to the type name.
+func joinSQLType(parts []string) string {
+	var sb strings.Builder
+	for i, part := range parts {
+		if i > 0 && !strings.HasPrefix(part, "(") && part != "[" && part != "]" {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(part)
+	}
+	return sb.String()
+}
+
+// acceptKeywords consumes the given keyword sequence if it is next.
+func (p *sqlParser) acceptKeywords(keywords ...string) bool {
+	for i, kw := range keywords {
+		if p.pos+i >= len(p.tokens) {
+			return false
+		}
+		tok := p.tokens[p.pos+i]
+		if tok.kind != sqlWord || !strings.EqualFold(tok.text, kw) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+// peekKeyword reports whether the next token is the given keyword.
+func (p *sqlParser) peekKeyword(kw string) bool {
+	tok := p.peek()
+	return tok.kind == sqlWord && strings.EqualFold(tok.text, kw)
+}
+
+// acceptSymbol consumes the given symbol if it is next.
+func (p *sqlParser) acceptSymbol(s string) bool {
+	if p.peekSymbol(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// peekSymbol reports whether the next token is the given symbol.
+func (p *sqlParser) peekSymbol(s string) bool {
+	tok := p.peek()
+	return tok.kind == sqlSymbol && tok.text == s
+}
+
+// expectSymbol consumes the given symbol or returns an error.
+func (p *sqlParser) expectSymbol(s string) error {
+	if !p.acceptSymbol(s) {
+		return p.errorf("expected %q", s)
+	}
+	return nil
+}
+
+// peek returns the next token without consuming it.
+func (p *sqlParser) peek() sqlToken {
+	if p.atEnd() {
+		return sqlToken{kind: sqlSymbol}
+	}
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the next token.
+func (p *sqlParser) next() sqlToken {
+	tok := p.peek()
+	if !p.atEnd() {
+		p.pos++
+	}
+	return tok
+}
+
+// atEnd reports whether all tokens have been consumed.
+func (p *sqlParser) atEnd() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// errorf returns a parse error annotated with the current line.
+func (p *sqlParser) errorf(format string, args ...any) error {
+	line := 0
+	switch {
+	case len(p.tokens) == 0:
+	case p.atEnd():
+		line = p.tokens[len(p.tokens)-1].line
+	default:
+		line = p.tokens[p.pos].line
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
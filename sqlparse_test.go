@@ -0,0 +1,376 @@
+package erd
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testDDL = `
+-- Users of the shop
+CREATE TABLE IF NOT EXISTS public.users (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    bio TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE TABLE profiles (
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    avatar TEXT DEFAULT 'none'::text,
+    CONSTRAINT profiles_pkey PRIMARY KEY (user_id)
+);
+
+CREATE TABLE orders (
+    id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    total NUMERIC(10, 2),
+    tags TEXT[]
+);
+
+/* constraints added separately */
+ALTER TABLE ONLY orders ADD CONSTRAINT orders_pkey PRIMARY KEY (id);
+ALTER TABLE orders ADD CONSTRAINT orders_user_fk FOREIGN KEY (user_id) REFERENCES public.users (id);
+
+CREATE TABLE groups (id UUID PRIMARY KEY);
+
+CREATE TABLE users_groups (
+    user_id BIGINT NOT NULL REFERENCES users (id),
+    group_id UUID NOT NULL REFERENCES groups (id),
+    PRIMARY KEY (user_id, group_id)
+);
+
+COMMENT ON TABLE users IS 'Registered customers';
+COMMENT ON COLUMN public.users.bio IS 'Free text';
+CREATE INDEX orders_user_idx ON orders (user_id);
+`
+
+func TestFromSQL(t *testing.T) {
+	diagram, err := FromSQL("Shop", strings.NewReader(testDDL), PostgreSQL)
+	if err != nil {
+		t.Fatalf("FromSQL() unexpected error: %v", err)
+	}
+
+	if diagram.Title != "Shop" {
+		t.Errorf("expected title 'Shop', got %q", diagram.Title)
+	}
+	if len(diagram.Entities) != 4 {
+		t.Fatalf("expected 4 entities (join table excluded), got %d", len(diagram.Entities))
+	}
+
+	users := diagram.Entities["users"]
+	if users == nil {
+		t.Fatal("users entity not found")
+	}
+	if users.Package == nil || *users.Package != "public" {
+		t.Errorf("expected users package 'public', got %v", users.Package)
+	}
+	if users.Note == nil || *users.Note != "Registered customers" {
+		t.Errorf("expected users note from COMMENT ON TABLE, got %v", users.Note)
+	}
+
+	tests := []struct {
+		entity   string
+		attr     string
+		typ      string
+		key      *KeyType
+		nullable bool
+	}{
+		{"users", "id", "int64", ptr(PrimaryKey), false},
+		{"users", "email", "string", ptr(UniqueKey), false},
+		{"users", "bio", "string", nil, true},
+		{"users", "created_at", "time.Time", nil, false},
+		{"profiles", "user_id", "int64", ptr(PrimaryKey), false},
+		{"profiles", "avatar", "string", nil, true},
+		{"orders", "id", "int64", ptr(PrimaryKey), false},
+		{"orders", "user_id", "int64", ptr(ForeignKey), false},
+		{"orders", "total", "float64", nil, true},
+		{"orders", "tags", "[]string", nil, true},
+		{"groups", "id", "string", ptr(PrimaryKey), false},
+	}
+	for _, tt := range tests {
+		attr := findAttribute(diagram.Entities[tt.entity], tt.attr)
+		if attr == nil {
+			t.Errorf("%s.%s not found", tt.entity, tt.attr)
+			continue
+		}
+		if attr.Type != tt.typ {
+			t.Errorf("%s.%s type = %q, want %q", tt.entity, tt.attr, attr.Type, tt.typ)
+		}
+		if attr.Nullable != tt.nullable {
+			t.Errorf("%s.%s nullable = %v, want %v", tt.entity, tt.attr, attr.Nullable, tt.nullable)
+		}
+		if (tt.key == nil) != (attr.Key == nil) || (tt.key != nil && *tt.key != *attr.Key) {
+			t.Errorf("%s.%s key = %v, want %v", tt.entity, tt.attr, attr.Key, tt.key)
+		}
+	}
+
+	if bio := findAttribute(users, "bio"); bio == nil || bio.Note == nil || *bio.Note != "Free text" {
+		t.Error("expected bio note from COMMENT ON COLUMN")
+	}
+
+	rels := map[string]Cardinality{}
+	for _, rel := range diagram.Relationships {
+		rels[rel.From+"->"+rel.To+":"+rel.Field] = rel.Cardinality
+	}
+	wantRels := map[string]Cardinality{
+		"profiles->users:user_id": OneToOne,
+		"orders->users:user_id":   ManyToOne,
+		"users->groups:groups":    ManyToMany,
+	}
+	for key, want := range wantRels {
+		if got, ok := rels[key]; !ok || got != want {
+			t.Errorf("relationship %s = %q, want %q (all: %v)", key, got, want, rels)
+		}
+	}
+	if len(diagram.Relationships) != len(wantRels) {
+		t.Errorf("expected %d relationships, got %v", len(wantRels), rels)
+	}
+
+	if errs := diagram.Validate(); len(errs) > 0 {
+		t.Errorf("Validate() unexpected errors: %v", errs)
+	}
+}
+
+func TestFromSQL_MySQL(t *testing.T) {
+	ddl := "CREATE TABLE `accounts` (\n" +
+		"  `id` INT UNSIGNED NOT NULL AUTO_INCREMENT,\n" +
+		"  `active` TINYINT(1) NOT NULL DEFAULT 1,\n" +
+		"  `name` VARCHAR(64) COMMENT 'Display name',\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE KEY `name_idx` (`name`),\n" +
+		"  KEY `active_idx` (`active`)\n" +
+		") ENGINE=InnoDB COMMENT='Customer accounts'; # trailing comment\n"
+
+	diagram, err := FromSQL("Accounts", strings.NewReader(ddl), MySQL)
+	if err != nil {
+		t.Fatalf("FromSQL() unexpected error: %v", err)
+	}
+
+	accounts := diagram.Entities["accounts"]
+	if accounts == nil {
+		t.Fatal("accounts entity not found")
+	}
+	if accounts.Note == nil || *accounts.Note != "Customer accounts" {
+		t.Errorf("expected table comment, got %v", accounts.Note)
+	}
+	if id := findAttribute(accounts, "id"); id == nil || id.Type != "uint32" {
+		t.Errorf("expected id to be uint32, got %+v", id)
+	}
+	if active := findAttribute(accounts, "active"); active == nil || active.Type != "bool" {
+		t.Errorf("expected active to be bool, got %+v", active)
+	}
+	name := findAttribute(accounts, "name")
+	if name == nil || name.Key == nil || *name.Key != UniqueKey || name.Note == nil || *name.Note != "Display name" {
+		t.Errorf("expected name to be unique with note, got %+v", name)
+	}
+}
+
+func TestFromSQL_RoundTrip(t *testing.T) {
+	for _, dialect := range []Dialect{PostgreSQL, MySQL, SQLite} {
+		t.Run(string(dialect), func(t *testing.T) {
+			ddl, err := testSQLDiagram().ToSQL(dialect)
+			if err != nil {
+				t.Fatalf("ToSQL() unexpected error: %v", err)
+			}
+
+			diagram, err := FromSQL("Shop", strings.NewReader(ddl), dialect)
+			if err != nil {
+				t.Fatalf("FromSQL() unexpected error: %v", err)
+			}
+
+			if len(diagram.Entities) != 4 {
+				t.Errorf("expected 4 entities, got %d", len(diagram.Entities))
+			}
+			if bio := findAttribute(diagram.Entities["User"], "Bio"); bio == nil || !bio.Nullable {
+				t.Errorf("expected User.Bio to round-trip as nullable, got %+v", bio)
+			}
+
+			var m2m *Relationship
+			for _, rel := range diagram.Relationships {
+				if rel.Cardinality == ManyToMany {
+					m2m = rel
+				}
+			}
+			if m2m == nil || m2m.From != "User" || m2m.To != "Group" || m2m.Field != "Groups" {
+				t.Errorf("expected join table to round-trip as many-to-many, got %+v", m2m)
+			}
+		})
+	}
+}
+
+func TestFromSQL_CompositeKeys(t *testing.T) {
+	ddl := `
+CREATE TABLE orders (
+    tenant_id BIGINT NOT NULL,
+    id BIGINT NOT NULL,
+    number TEXT NOT NULL,
+    PRIMARY KEY (tenant_id, id),
+    UNIQUE (tenant_id, number)
+);
+CREATE TABLE line_items (
+    tenant_id BIGINT NOT NULL,
+    order_id BIGINT NOT NULL,
+    line INTEGER NOT NULL,
+    PRIMARY KEY (tenant_id, order_id, line),
+    FOREIGN KEY (tenant_id, order_id) REFERENCES orders (tenant_id, id)
+);
+`
+	d, err := FromSQL("Orders", strings.NewReader(ddl), PostgreSQL)
+	if err != nil {
+		t.Fatalf("FromSQL() unexpected error: %v", err)
+	}
+	if errs := d.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() unexpected errors: %v", errs)
+	}
+
+	items := d.Entities["line_items"]
+	if items == nil {
+		t.Fatal("line_items entity not found")
+	}
+	var fk *Key
+	for _, key := range items.Keys {
+		if key.Type == ForeignKey {
+			fk = key
+		}
+	}
+	if fk == nil || fk.Reference == nil {
+		t.Fatalf("expected a composite foreign key on line_items, got %+v", items.Keys)
+	}
+	if fk.Reference.Entity != "orders" || len(fk.Attributes) != 2 || len(fk.Reference.Attributes) != 2 {
+		t.Errorf("expected foreign key of 2 attributes referencing orders, got %+v -> %+v", fk, fk.Reference)
+	}
+
+	var rel *Relationship
+	for _, r := range d.Relationships {
+		if r.From == "line_items" {
+			rel = r
+		}
+	}
+	if rel == nil || !slices.Equal(rel.FromAttributes, []string{"tenant_id", "order_id"}) || !slices.Equal(rel.ToAttributes, []string{"tenant_id", "id"}) {
+		t.Errorf("expected relationship joining line_items (tenant_id, order_id) to orders (tenant_id, id), got %+v", rel)
+	}
+
+	orders := d.Entities["orders"]
+	if pk := primaryKey(orders); len(pk) != 2 {
+		t.Errorf("expected orders to have a 2-attribute primary key, got %d", len(pk))
+	}
+	if !slices.ContainsFunc(orders.Keys, func(key *Key) bool {
+		return key.Type == UniqueKey && len(key.Attributes) == 2
+	}) {
+		t.Errorf("expected a composite unique key on orders, got %+v", orders.Keys)
+	}
+}
+
+func TestFromSQL_SchemaCollision(t *testing.T) {
+	ddl := `
+CREATE TABLE billing.accounts (id BIGINT PRIMARY KEY);
+CREATE TABLE auth.accounts (id BIGINT PRIMARY KEY, password TEXT NOT NULL);
+CREATE TABLE billing.invoices (
+    id BIGINT PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts (id)
+);
+CREATE TABLE auth.sessions (
+    token TEXT PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES auth.accounts (id)
+);
+`
+	d, err := FromSQL("Accounts", strings.NewReader(ddl), PostgreSQL)
+	if err != nil {
+		t.Fatalf("FromSQL() unexpected error: %v", err)
+	}
+
+	for _, key := range []string{"billing.accounts", "auth.accounts", "invoices", "sessions"} {
+		if _, ok := d.Entities[key]; !ok {
+			t.Errorf("expected entity keyed %q", key)
+		}
+	}
+
+	// Unqualified references resolve within the referencing table's schema
+	want := map[string]string{"invoices": "billing.accounts", "sessions": "auth.accounts"}
+	for _, rel := range d.Relationships {
+		if want[rel.From] != rel.To {
+			t.Errorf("relationship %s -> %s, want %s -> %s", rel.From, rel.To, rel.From, want[rel.From])
+		}
+	}
+	if errs := d.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() unexpected errors: %v", errs)
+	}
+}
+
+func TestFromSQL_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ddl     string
+		dialect Dialect
+	}{
+		{"unsupported dialect", "CREATE TABLE a (id INT);", Dialect("oracle")},
+		{"unterminated string", "CREATE TABLE a (id INT COMMENT 'oops);", MySQL},
+		{"unterminated comment", "/* CREATE TABLE a (id INT);", PostgreSQL},
+		{"unbalanced parenthesis", "CREATE TABLE a (id INT", PostgreSQL},
+		{"missing references", "CREATE TABLE a (id INT, FOREIGN KEY (id) b (id));", PostgreSQL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromSQL("Test", strings.NewReader(tt.ddl), tt.dialect); err == nil {
+				t.Error("FromSQL() expected error")
+			}
+		})
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestFromSQL_ReadError(t *testing.T) {
+	if _, err := FromSQL("Test", failingReader{}, PostgreSQL); err == nil {
+		t.Error("FromSQL() expected error from reader")
+	}
+}
+
+func TestGoTypeForSQL(t *testing.T) {
+	tests := []struct {
+		sqlType string
+		dialect Dialect
+		want    string
+	}{
+		{"VARCHAR(255)", MySQL, "string"},
+		{"character varying(20)", PostgreSQL, "string"},
+		{"INTEGER", PostgreSQL, "int32"},
+		{"INTEGER", SQLite, "int64"},
+		{"BIGINT UNSIGNED", MySQL, "uint64"},
+		{"TINYINT(1)", MySQL, "bool"},
+		{"TINYINT", MySQL, "int8"},
+		{"REAL", PostgreSQL, "float32"},
+		{"REAL", SQLite, "float64"},
+		{"DOUBLE PRECISION", PostgreSQL, "float64"},
+		{"TIMESTAMPTZ", PostgreSQL, "time.Time"},
+		{"BYTEA", PostgreSQL, "[]byte"},
+		{"JSONB", PostgreSQL, "json.RawMessage"},
+		{"integer[]", PostgreSQL, "[]int32"},
+		{"geometry", PostgreSQL, "geometry"},
+	}
+
+	for _, tt := range tests {
+		if got := goTypeForSQL(tt.sqlType, tt.dialect); got != tt.want {
+			t.Errorf("goTypeForSQL(%q, %s) = %q, want %q", tt.sqlType, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func findAttribute(entity *Entity, name string) *Attribute {
+	if entity == nil {
+		return nil
+	}
+	for _, attr := range entity.Attributes {
+		if attr.Name == name {
+			return attr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package erd
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+)
+
+// Direction selects which relationships [Diagram.Neighborhood] follows.
+type Direction string
+
+// Direction constants.
+const (
+	// Outgoing follows relationships from an entity to the entities it
+	// references.
+	Outgoing Direction = "outgoing"
+	// Incoming follows relationships from the entities referencing an entity.
+	Incoming Direction = "incoming"
+	// Both follows relationships either way.
+	Both Direction = "both"
+)
+
+// Neighborhood returns a new diagram with the entity and every entity
+// reachable from it within depth relationship hops in the given direction,
+// along with all relationships among them. The entity is looked up as by
+// [Diagram.Entity]; if it does not exist the result has no entities. A depth
+// of zero keeps the entity alone.
+//
+// Entities keep their keys, and entities and relationships are copied, so
+// the result can be changed without affecting the diagram.
+func (d *Diagram) Neighborhood(entity string, depth int, direction Direction) *Diagram {
+	keep := make(map[string]bool)
+	start, ok := d.resolveEntity(entity)
+	if !ok {
+		return d.subgraph(keep)
+	}
+
+	keep[start] = true
+	frontier := []string{start}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		var next []string
+		visit := func(key string) {
+			if _, ok := d.Entities[key]; ok && !keep[key] {
+				keep[key] = true
+				next = append(next, key)
+			}
+		}
+		for _, key := range frontier {
+			for _, rel := range d.Relationships {
+				if rel.From == key && direction != Incoming {
+					visit(rel.To)
+				}
+				if rel.To == key && direction != Outgoing {
+					visit(rel.From)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return d.subgraph(keep)
+}
+
+// subgraph copies the diagram with only the entities whose keys are kept and
+// the relationships between them. Foreign keys referencing dropped entities
+// are dropped too.
+func (d *Diagram) subgraph(keep map[string]bool) *Diagram {
+	sub := NewDiagram(d.Title)
+	sub.Description = d.Description
+
+	for name, entity := range d.Entities {
+		if !keep[name] {
+			continue
+		}
+		clone := cloneEntity(entity)
+		clone.Keys = slices.DeleteFunc(clone.Keys, func(key *Key) bool {
+			if key.Reference == nil {
+				return false
+			}
+			target, ok := d.resolveEntity(key.Reference.Entity)
+			return ok && !keep[target]
+		})
+		sub.Entities[name] = clone
+	}
+	for _, rel := range d.Relationships {
+		if keep[rel.From] && keep[rel.To] {
+			clone := *rel
+			sub.Relationships = append(sub.Relationships, &clone)
+		}
+	}
+
+	return sub
+}
+
+// FilterSpec selects the entities and attributes [Diagram.Filter] keeps.
+// Empty fields select everything.
+//
+// Entity globs use [path.Match] syntax and match an entity's name or its
+// qualified name. Package globs match [Entity.Package] with the same syntax,
+// so "github.com/app/internal/*" matches the packages directly under
+// internal; entities without a package never match. Attribute patterns match
+// attribute names.
+type FilterSpec struct {
+	// IncludeAttributes keeps only attributes whose names match.
+	IncludeAttributes *regexp.Regexp
+
+	// ExcludeAttributes drops attributes whose names match, such as
+	// audit columns with ^(Created|Updated)At$.
+	ExcludeAttributes *regexp.Regexp
+
+	// Include keeps only entities matching one of the globs.
+	Include []string
+
+	// Exclude drops entities matching one of the globs.
+	Exclude []string
+
+	// IncludePackages keeps only entities in packages matching one of the
+	// globs.
+	IncludePackages []string
+
+	// ExcludePackages drops entities in packages matching one of the globs.
+	ExcludePackages []string
+}
+
+// Filter returns a new diagram with the entities and attributes selected by
+// the spec. An entity is kept if it matches every include list that is set
+// and no exclude list. Relationships are kept only if both of their entities
+// are, so the result has no dangling relationships. Key groups listing a
+// dropped attribute are dropped, as are the attributes a relationship joins
+// when one of them is. Entities keep their other keys, and entities and
+// relationships are copied, so the result can be changed without affecting
+// the diagram.
+//
+// It returns an error if a glob is malformed.
+func (d *Diagram) Filter(spec FilterSpec) (*Diagram, error) {
+	for _, globs := range [][]string{spec.Include, spec.Exclude, spec.IncludePackages, spec.ExcludePackages} {
+		for _, glob := range globs {
+			if _, err := path.Match(glob, ""); err != nil {
+				return nil, fmt.Errorf("invalid glob %q: %w", glob, err)
+			}
+		}
+	}
+
+	keep := make(map[string]bool)
+	for key, entity := range d.Entities {
+		keep[key] = spec.keepsEntity(entity)
+	}
+
+	filtered := d.subgraph(keep)
+	if spec.IncludeAttributes == nil && spec.ExcludeAttributes == nil {
+		return filtered, nil
+	}
+	for _, entity := range filtered.Entities {
+		attrs := entity.Attributes[:0]
+		for _, attr := range entity.Attributes {
+			if spec.keepsAttribute(attr) {
+				attrs = append(attrs, attr)
+			}
+		}
+		entity.Attributes = attrs
+		entity.Keys = slices.DeleteFunc(entity.Keys, func(key *Key) bool {
+			return !hasAttributes(entity, key.Attributes)
+		})
+	}
+	for _, rel := range filtered.Relationships {
+		if !hasAttributes(filtered.Entities[rel.From], rel.FromAttributes) || !hasAttributes(filtered.Entities[rel.To], rel.ToAttributes) {
+			rel.FromAttributes, rel.ToAttributes = nil, nil
+		}
+	}
+	return filtered, nil
+}
+
+// hasAttributes reports whether the entity has every named attribute.
+func hasAttributes(entity *Entity, names []string) bool {
+	return !slices.ContainsFunc(names, func(name string) bool {
+		return attributeNamed(entity, name) == nil
+	})
+}
+
+// keepsEntity reports whether the spec selects the entity.
+func (s FilterSpec) keepsEntity(entity *Entity) bool {
+	names := []string{entity.Name, entity.QualifiedName()}
+	var pkg []string
+	if entity.Package != nil && *entity.Package != "" {
+		pkg = []string{*entity.Package}
+	}
+
+	if len(s.Include) > 0 && !matchesGlob(s.Include, names) {
+		return false
+	}
+	if len(s.IncludePackages) > 0 && !matchesGlob(s.IncludePackages, pkg) {
+		return false
+	}
+	return !matchesGlob(s.Exclude, names) && !matchesGlob(s.ExcludePackages, pkg)
+}
+
+// keepsAttribute reports whether the spec selects the attribute.
+func (s FilterSpec) keepsAttribute(attr *Attribute) bool {
+	if s.IncludeAttributes != nil && !s.IncludeAttributes.MatchString(attr.Name) {
+		return false
+	}
+	return s.ExcludeAttributes == nil || !s.ExcludeAttributes.MatchString(attr.Name)
+}
+
+// matchesGlob reports whether any of the values matches any of the globs.
+func matchesGlob(globs, values []string) bool {
+	for _, glob := range globs {
+		for _, value := range values {
+			if ok, _ := path.Match(glob, value); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
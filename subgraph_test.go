@@ -0,0 +1,300 @@
+package erd
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// testChainDiagram relates User -> Order -> Product -> Supplier, with
+// Review -> Product and an unrelated Coupon.
+func testChainDiagram() *Diagram {
+	d := NewDiagram("Shop").WithDescription("Shop domain")
+	for _, name := range []string{"User", "Order", "Product", "Supplier", "Review", "Coupon"} {
+		d.AddEntity(NewEntity(name).AddAttribute(NewAttribute("ID", "string").WithPrimaryKey()))
+	}
+	return d.
+		AddRelationship(NewRelationship("User", "Order", "Orders", OneToMany)).
+		AddRelationship(NewRelationship("Order", "Product", "Products", ManyToMany)).
+		AddRelationship(NewRelationship("Product", "Supplier", "Supplier", ManyToOne)).
+		AddRelationship(NewRelationship("Review", "Product", "Product", ManyToOne)).
+		AddRelationship(NewRelationship("Review", "User", "Author", ManyToOne))
+}
+
+// relationshipFields returns the field of every relationship in order.
+func relationshipFields(d *Diagram) []string {
+	var fields []string
+	for _, rel := range d.Relationships {
+		fields = append(fields, rel.From+"."+rel.Field)
+	}
+	return fields
+}
+
+func TestDiagram_Neighborhood(t *testing.T) {
+	tests := []struct {
+		name          string
+		entity        string
+		depth         int
+		direction     Direction
+		entities      []string
+		relationships []string
+	}{
+		{
+			name:      "depth zero",
+			entity:    "Order",
+			direction: Both,
+			entities:  []string{"Order"},
+		},
+		{
+			name:          "outgoing",
+			entity:        "Order",
+			depth:         1,
+			direction:     Outgoing,
+			entities:      []string{"Order", "Product"},
+			relationships: []string{"Order.Products"},
+		},
+		{
+			name:          "incoming",
+			entity:        "Product",
+			depth:         1,
+			direction:     Incoming,
+			entities:      []string{"Order", "Product", "Review"},
+			relationships: []string{"Order.Products", "Review.Product"},
+		},
+		{
+			name:          "both within two hops",
+			entity:        "Order",
+			depth:         2,
+			direction:     Both,
+			entities:      []string{"Order", "Product", "Review", "Supplier", "User"},
+			relationships: []string{"User.Orders", "Order.Products", "Product.Supplier", "Review.Product", "Review.Author"},
+		},
+		{
+			name:          "outgoing two hops",
+			entity:        "User",
+			depth:         2,
+			direction:     Outgoing,
+			entities:      []string{"Order", "Product", "User"},
+			relationships: []string{"User.Orders", "Order.Products"},
+		},
+		{
+			name:      "unrelated entity",
+			entity:    "Coupon",
+			depth:     3,
+			direction: Both,
+			entities:  []string{"Coupon"},
+		},
+		{
+			name:      "missing entity",
+			entity:    "Invoice",
+			depth:     1,
+			direction: Both,
+			entities:  []string{},
+		},
+	}
+
+	d := testChainDiagram()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := d.Neighborhood(tt.entity, tt.depth, tt.direction)
+			if sub.Title != "Shop" || sub.Description != d.Description {
+				t.Errorf("Neighborhood() should keep the title and description")
+			}
+			if got := sub.entityNames(); !reflect.DeepEqual(got, tt.entities) {
+				t.Errorf("entities = %v, want %v", got, tt.entities)
+			}
+			if got := relationshipFields(sub); !reflect.DeepEqual(got, tt.relationships) {
+				t.Errorf("relationships = %v, want %v", got, tt.relationships)
+			}
+		})
+	}
+}
+
+func TestDiagram_Neighborhood_Copies(t *testing.T) {
+	d := testChainDiagram()
+	sub := d.Neighborhood("Order", 1, Both)
+
+	sub.Entities["Order"].Attributes[0].Name = "OrderID"
+	sub.Relationships[0].Field = "Purchases"
+
+	if d.Entities["Order"].Attributes[0].Name != "ID" {
+		t.Error("changing the neighborhood should not change the diagram's entities")
+	}
+	if d.Relationships[0].Field != "Orders" {
+		t.Error("changing the neighborhood should not change the diagram's relationships")
+	}
+}
+
+func TestDiagram_Neighborhood_QualifiedKeys(t *testing.T) {
+	d := testAccounts()
+
+	sub := d.Neighborhood("billing.Account", 1, Incoming)
+	if got := sub.entityNames(); !reflect.DeepEqual(got, []string{"Invoice", "billing.Account"}) {
+		t.Errorf("entities = %v, want [Invoice billing.Account]", got)
+	}
+	if errs := sub.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() unexpected errors: %v", errs)
+	}
+
+	if sub := d.Neighborhood("Account", 1, Both); len(sub.Entities) != 0 {
+		t.Errorf("ambiguous name should match no entity, got %v", sub.entityNames())
+	}
+}
+
+func TestDiagram_Filter(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          FilterSpec
+		entities      []string
+		relationships []string
+	}{
+		{
+			name:          "empty spec",
+			entities:      []string{"Order", "Session", "Tag", "User"},
+			relationships: []string{"User.Orders"},
+		},
+		{
+			name:          "include globs",
+			spec:          FilterSpec{Include: []string{"U*", "Ord?r"}},
+			entities:      []string{"Order", "User"},
+			relationships: []string{"User.Orders"},
+		},
+		{
+			name:     "exclude drops dangling relationships",
+			spec:     FilterSpec{Exclude: []string{"Order", "Tag"}},
+			entities: []string{"Session", "User"},
+		},
+		{
+			name:     "qualified name glob",
+			spec:     FilterSpec{Include: []string{"github.com/shop/auth.*"}},
+			entities: []string{"Session", "User"},
+		},
+		{
+			name:          "include packages",
+			spec:          FilterSpec{IncludePackages: []string{"github.com/shop/*"}},
+			entities:      []string{"Order", "Session", "User"},
+			relationships: []string{"User.Orders"},
+		},
+		{
+			name:     "exclude packages",
+			spec:     FilterSpec{ExcludePackages: []string{"github.com/shop/billing"}},
+			entities: []string{"Session", "Tag", "User"},
+		},
+		{
+			name:     "include and exclude",
+			spec:     FilterSpec{IncludePackages: []string{"github.com/shop/auth"}, Exclude: []string{"Session"}},
+			entities: []string{"User"},
+		},
+	}
+
+	d := testPackagedDiagram()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := d.Filter(tt.spec)
+			if err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+			if got := filtered.entityNames(); !reflect.DeepEqual(got, tt.entities) {
+				t.Errorf("entities = %v, want %v", got, tt.entities)
+			}
+			if got := relationshipFields(filtered); !reflect.DeepEqual(got, tt.relationships) {
+				t.Errorf("relationships = %v, want %v", got, tt.relationships)
+			}
+			if errs := filtered.Validate(); len(errs) != 0 {
+				t.Errorf("Validate() unexpected errors: %v", errs)
+			}
+		})
+	}
+
+	if len(d.Entities) != 4 || len(d.Relationships) != 1 {
+		t.Error("Filter() should not modify the diagram")
+	}
+}
+
+func TestDiagram_Filter_Attributes(t *testing.T) {
+	d := testOptionsDiagram()
+
+	filtered, err := d.Filter(FilterSpec{ExcludeAttributes: regexp.MustCompile(`^(Created|Updated)$|^Meta`)})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if got := attributeNames(filtered.Entities["User"]); !reflect.DeepEqual(got, []string{"ID", "Email"}) {
+		t.Errorf("User attributes = %v, want [ID Email]", got)
+	}
+	if got := attributeNames(d.Entities["User"]); len(got) != 4 {
+		t.Errorf("Filter() should not modify the diagram's attributes, got %v", got)
+	}
+
+	filtered, err = d.Filter(FilterSpec{IncludeAttributes: regexp.MustCompile(`ID$`), ExcludeAttributes: regexp.MustCompile(`^User`)})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if got := attributeNames(filtered.Entities["Order"]); !reflect.DeepEqual(got, []string{"ID"}) {
+		t.Errorf("Order attributes = %v, want [ID]", got)
+	}
+}
+
+func TestDiagram_Subgraph_Keys(t *testing.T) {
+	d := testCompositeDiagram()
+
+	// Foreign keys referencing dropped entities are dropped
+	sub := d.Neighborhood("Order", 0, Both)
+	if got := keyNames(sub.Entities["Order"]); !reflect.DeepEqual(got, []string{"uk_number"}) {
+		t.Errorf("Order keys = %v, want [uk_number]", got)
+	}
+	if got := keyNames(d.Entities["Order"]); len(got) != 2 {
+		t.Errorf("Neighborhood() should not modify the diagram's keys, got %v", got)
+	}
+
+	// Keys listing dropped attributes are dropped
+	filtered, err := d.Filter(FilterSpec{ExcludeAttributes: regexp.MustCompile(`^Number$`)})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if got := keyNames(filtered.Entities["Order"]); !reflect.DeepEqual(got, []string{"fk_tenant"}) {
+		t.Errorf("Order keys = %v, want [fk_tenant]", got)
+	}
+	if errs := filtered.Validate(); len(errs) != 0 {
+		t.Errorf("filtered diagram should be valid, got %v", errs)
+	}
+
+	// Relationships forget the attributes they join when one is dropped
+	d.Relationships[0].WithAttributes([]string{"TenantID", "ID"}, []string{"TenantID", "OrderID"})
+	filtered, err = d.Filter(FilterSpec{ExcludeAttributes: regexp.MustCompile(`^OrderID$`)})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if rel := filtered.Relationships[0]; rel.FromAttributes != nil || rel.ToAttributes != nil {
+		t.Errorf("relationship attributes = %v, %v, want none", rel.FromAttributes, rel.ToAttributes)
+	}
+	if len(d.Relationships[0].FromAttributes) != 2 {
+		t.Error("Filter() should not modify the diagram's relationships")
+	}
+	if errs := filtered.Validate(); len(errs) != 0 {
+		t.Errorf("filtered diagram should be valid, got %v", errs)
+	}
+}
+
+func TestDiagram_Filter_InvalidGlob(t *testing.T) {
+	if _, err := testPackagedDiagram().Filter(FilterSpec{ExcludePackages: []string{"[a-"}}); err == nil {
+		t.Error("Filter() expected error for malformed glob")
+	}
+}
+
+// keyNames returns the names of an entity's key groups in order.
+func keyNames(entity *Entity) []string {
+	var names []string
+	for _, key := range entity.Keys {
+		names = append(names, key.Name)
+	}
+	return names
+}
+
+// attributeNames returns the names of an entity's attributes in order.
+func attributeNames(entity *Entity) []string {
+	var names []string
+	for _, attr := range entity.Attributes {
+		names = append(names, attr.Name)
+	}
+	return names
+}
@@ -0,0 +1,69 @@
+// Package models is a fixture for static package scanning tests.
+package models
+
+import "time"
+
+// User is a registered customer.
+type User struct {
+	ID        string `erd:"pk"`
+	Email     string `erd:"uk"`
+	Name      string
+	CreatedAt time.Time
+	Profile   *Profile
+	Orders    []Order
+	secret    string
+}
+
+// Profile holds optional user details.
+type Profile struct {
+	ID     string `erd:"pk"`
+	Bio    *string
+	Avatar *string `erd:"note:Profile picture URL"`
+}
+
+// Order is a purchase made by a user.
+type Order struct {
+	ID     string `erd:"pk"`
+	UserID string `erd:"fk"`
+	Total  float64
+	Lines  []*LineItem
+}
+
+// LineItem is a single product on an order.
+type LineItem struct {
+	SKU      string `erd:"pk"`
+	Quantity int
+}
+
+// Address is embedded into Company.
+type Address struct {
+	Street string
+	City   string
+}
+
+// Company groups employees.
+type Company struct {
+	ID string `erd:"pk"`
+	Address
+	Employees map[string]Employee
+}
+
+// Employee works for a company.
+type Employee struct {
+	ID   string `erd:"pk"`
+	Name string
+}
+
+// Status is not a struct and must not become an entity.
+type Status string
+
+// Page is generic and must not become an entity.
+type Page[T any] struct {
+	Items []T
+}
+
+type internal struct {
+	ID string
+}
+
+var _ = internal{}.ID
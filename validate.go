@@ -36,18 +36,21 @@ func (d *Diagram) Validate() []ValidationError {
 		})
 	}
 
-	// Validate each entity
+	// Validate each entity and the entities its foreign keys reference
 	for name, entity := range d.Entities {
-		if entityErrors := entity.Validate(); len(entityErrors) > 0 {
-			for _, err := range entityErrors {
-				errors = append(errors, ValidationError{
-					Field:   fmt.Sprintf("Entity[%s].%s", name, err.Field),
-					Message: err.Message,
-				})
-			}
+		entityErrors := entity.Validate()
+		entityErrors = append(entityErrors, validateKeyReferences(d.Entities, entity)...)
+		for _, err := range entityErrors {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("Entity[%s].%s", name, err.Field),
+				Message: err.Message,
+			})
 		}
 	}
 
+	// Check entities are defined once under their own names
+	errors = append(errors, d.validateIdentity()...)
+
 	// Validate relationships reference valid entities
 	for i, rel := range d.Relationships {
 		if relErrors := rel.ValidateAgainst(d.Entities); len(relErrors) > 0 {
@@ -95,6 +98,89 @@ func (e *Entity) Validate() []ValidationError {
 		}
 	}
 
+	// Validate each key group
+	for i, key := range e.Keys {
+		for _, err := range e.validateKey(key) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("Key[%d].%s", i, err.Field),
+				Message: err.Message,
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateKey checks a key group lists attributes of the entity.
+func (e *Entity) validateKey(key *Key) []ValidationError {
+	var errors []ValidationError
+
+	if !isValidKeyType(key.Type) {
+		errors = append(errors, ValidationError{
+			Field:   "Type",
+			Message: fmt.Sprintf("invalid key type: %s", key.Type),
+		})
+	}
+
+	if len(key.Attributes) == 0 {
+		errors = append(errors, ValidationError{
+			Field:   "Attributes",
+			Message: "key must list at least one attribute",
+		})
+	}
+	for _, name := range key.Attributes {
+		if attributeNamed(e, name) == nil {
+			errors = append(errors, ValidationError{
+				Field:   "Attributes",
+				Message: fmt.Sprintf("attribute '%s' does not exist", name),
+			})
+		}
+	}
+
+	if key.Reference != nil && key.Type != ForeignKey {
+		errors = append(errors, ValidationError{
+			Field:   "Reference",
+			Message: "only foreign keys can reference another entity",
+		})
+	}
+
+	return errors
+}
+
+// validateKeyReferences checks that the foreign keys of an entity refer to
+// existing entities and attributes, one for each attribute of the key.
+func validateKeyReferences(entities map[string]*Entity, entity *Entity) []ValidationError {
+	var errors []ValidationError
+
+	for i, key := range entity.Keys {
+		if key.Reference == nil {
+			continue
+		}
+		if err := validateEntityReference(entities, key.Reference.Entity); err != "" {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("Key[%d].Reference.Entity", i),
+				Message: err,
+			})
+			continue
+		}
+
+		target := referencedEntity(entities, key.Reference.Entity)
+		for _, name := range key.Reference.Attributes {
+			if attributeNamed(target, name) == nil {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("Key[%d].Reference.Attributes", i),
+					Message: fmt.Sprintf("attribute '%s' does not exist on entity '%s'", name, key.Reference.Entity),
+				})
+			}
+		}
+		if n := len(key.Reference.Attributes); n > 0 && n != len(key.Attributes) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("Key[%d].Reference.Attributes", i),
+				Message: fmt.Sprintf("key has %d attribute(s) but references %d", len(key.Attributes), n),
+			})
+		}
+	}
+
 	return errors
 }
 
@@ -141,10 +227,10 @@ func (r *Relationship) ValidateAgainst(entities map[string]*Entity) []Validation
 			Field:   "From",
 			Message: "from entity is required",
 		})
-	} else if _, exists := entities[r.From]; !exists {
+	} else if err := validateEntityReference(entities, r.From); err != "" {
 		errors = append(errors, ValidationError{
 			Field:   "From",
-			Message: fmt.Sprintf("entity '%s' does not exist", r.From),
+			Message: err,
 		})
 	}
 
@@ -154,10 +240,10 @@ func (r *Relationship) ValidateAgainst(entities map[string]*Entity) []Validation
 			Field:   "To",
 			Message: "to entity is required",
 		})
-	} else if _, exists := entities[r.To]; !exists {
+	} else if err := validateEntityReference(entities, r.To); err != "" {
 		errors = append(errors, ValidationError{
 			Field:   "To",
-			Message: fmt.Sprintf("entity '%s' does not exist", r.To),
+			Message: err,
 		})
 	}
 
@@ -177,9 +263,90 @@ func (r *Relationship) ValidateAgainst(entities map[string]*Entity) []Validation
 		})
 	}
 
+	// Validate the joined attributes exist and pair up
+	errors = append(errors, validateJoinedAttributes(entities, "FromAttributes", r.From, r.FromAttributes)...)
+	errors = append(errors, validateJoinedAttributes(entities, "ToAttributes", r.To, r.ToAttributes)...)
+	if len(r.FromAttributes) != len(r.ToAttributes) {
+		errors = append(errors, ValidationError{
+			Field:   "ToAttributes",
+			Message: fmt.Sprintf("relationship joins %d attribute(s) of '%s' but %d of '%s'", len(r.FromAttributes), r.From, len(r.ToAttributes), r.To),
+		})
+	}
+
+	// Validate participation agrees with the cardinality at each end
+	fromMany, toMany := cardinalityEnds(r.Cardinality)
+	errors = append(errors, validateParticipation("FromParticipation", r.FromParticipation, fromMany, r.Cardinality)...)
+	errors = append(errors, validateParticipation("ToParticipation", r.ToParticipation, toMany, r.Cardinality)...)
+
 	return errors
 }
 
+// validateJoinedAttributes checks the attributes a relationship joins exist
+// on the entity at that end. Missing entities are reported elsewhere.
+func validateJoinedAttributes(entities map[string]*Entity, field, entity string, attrs []string) []ValidationError {
+	if len(attrs) == 0 || validateEntityReference(entities, entity) != "" {
+		return nil
+	}
+
+	var errors []ValidationError
+	target := referencedEntity(entities, entity)
+	for _, name := range attrs {
+		if attributeNamed(target, name) == nil {
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("attribute '%s' does not exist on entity '%s'", name, entity),
+			})
+		}
+	}
+	return errors
+}
+
+// validateParticipation checks the participation set at one end of a
+// relationship allows many entities exactly when the cardinality does.
+func validateParticipation(field string, p *Participation, many bool, c Cardinality) []ValidationError {
+	switch {
+	case p == nil:
+		return nil
+	case !isValidParticipation(*p):
+		return []ValidationError{{
+			Field:   field,
+			Message: fmt.Sprintf("invalid participation: %s", *p),
+		}}
+	case isValidCardinality(c) && p.many() != many:
+		return []ValidationError{{
+			Field:   field,
+			Message: fmt.Sprintf("participation %s does not match cardinality %s", *p, c),
+		}}
+	default:
+		return nil
+	}
+}
+
+// validateEntityReference checks that a relationship endpoint names exactly
+// one entity, returning a message describing the problem otherwise.
+func validateEntityReference(entities map[string]*Entity, name string) string {
+	if _, exists := entities[name]; exists {
+		return ""
+	}
+	switch matches := entitiesNamed(entities, name); len(matches) {
+	case 0:
+		return fmt.Sprintf("entity '%s' does not exist", name)
+	case 1:
+		return ""
+	default:
+		return fmt.Sprintf("entity '%s' is ambiguous: use one of %s", name, strings.Join(matches, ", "))
+	}
+}
+
+// referencedEntity returns the entity a valid reference names, by key or by
+// unambiguous name.
+func referencedEntity(entities map[string]*Entity, name string) *Entity {
+	if entity, ok := entities[name]; ok {
+		return entity
+	}
+	return entities[entitiesNamed(entities, name)[0]]
+}
+
 // isValidKeyType checks if a key type is valid.
 func isValidKeyType(kt KeyType) bool {
 	switch kt {
@@ -190,6 +357,16 @@ func isValidKeyType(kt KeyType) bool {
 	}
 }
 
+// isValidParticipation checks if a participation is valid.
+func isValidParticipation(p Participation) bool {
+	switch p {
+	case ZeroOrOne, ExactlyOne, ZeroOrMore, OneOrMore:
+		return true
+	default:
+		return false
+	}
+}
+
 // isValidCardinality checks if a cardinality is valid.
 func isValidCardinality(c Cardinality) bool {
 	switch c {